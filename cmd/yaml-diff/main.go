@@ -6,47 +6,293 @@ import (
 	"io"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/sters/yaml-diff/yamldiff"
 )
 
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(v string) error {
+	*s = append(*s, v)
+
+	return nil
+}
+
+type doFlags struct {
+	ignoreEmptyFields *bool
+	ignoreZeroFields  *bool
+	matchArrayBy      *stringsFlag
+	orderedArrays     *bool
+	kubernetes        *bool
+	pairBy            *stringsFlag
+	similarity        *float64
+	ignore            *stringsFlag
+	selectPath        *string
+	numeric           *bool
+	floatTolerance    *float64
+	floatRelTolerance *float64
+	coerceTypes       *bool
+	reportCoerced     *bool
+}
+
+func newDoFlags(fs *flag.FlagSet) *doFlags {
+	f := &doFlags{
+		ignoreEmptyFields: fs.Bool("ignore-empty-fields", false, "Ignore empty field"),
+		ignoreZeroFields:  fs.Bool("ignore-zero-fields", false, "Ignore zero field"),
+		matchArrayBy:      &stringsFlag{},
+		pairBy:            &stringsFlag{},
+		ignore:            &stringsFlag{},
+		similarity:        fs.Float64("similarity-threshold", 0, "Report pairs less similar than the ratio (0-1) as removal and addition"),
+		orderedArrays:     fs.Bool("ordered-arrays", false, "Compare arrays as ordered sequences"),
+		kubernetes:        fs.Bool("k8s", false, "Pair documents by kubernetes object identity"),
+		numeric:           fs.Bool("numeric-equivalence", false, "Compare numbers by value across int/float types"),
+		floatTolerance:    fs.Float64("float-tolerance", 0, "Treat numbers within the absolute difference as the same"),
+		floatRelTolerance: fs.Float64("float-relative-tolerance", 0, "Treat numbers within the relative difference as the same"),
+		coerceTypes:       fs.Bool("coerce-types", false, "Treat scalars of the different types as the same if the string forms are the same"),
+		reportCoerced:     fs.Bool("report-coerced-types", false, "Report type changes of coerced scalars as coerced changes, implies --coerce-types"),
+		selectPath:        fs.String("select", "", "Diff only the value at the path in each document, e.g. .spec.template"),
+	}
+	fs.Var(f.matchArrayBy, "match-array-by", "Pair array elements by key, e.g. spec.containers=name (repeatable)")
+	fs.Var(f.pairBy, "pair-by", "Pair documents by the value at the path, e.g. metadata.name (repeatable)")
+	fs.Var(f.ignore, "ignore", "Ignore the values at the path, e.g. **.resourceVersion (repeatable)")
+
+	return f
+}
+
+func (f *doFlags) options() []yamldiff.DoOptionFunc {
+	opts := []yamldiff.DoOptionFunc{}
+	if *f.ignoreEmptyFields {
+		opts = append(opts, yamldiff.EmptyAsNull())
+	}
+	if *f.ignoreZeroFields {
+		opts = append(opts, yamldiff.ZeroAsNull())
+	}
+	if *f.orderedArrays {
+		opts = append(opts, yamldiff.OrderedArrays())
+	}
+	if *f.kubernetes {
+		if len(*f.pairBy) > 0 {
+			fmt.Fprintln(os.Stderr, "--k8s and --pair-by can't be used together")
+			os.Exit(1)
+		}
+
+		opts = append(opts, yamldiff.KubernetesIdentity())
+	}
+	if *f.numeric {
+		opts = append(opts, yamldiff.NumericEquivalence())
+	}
+	if *f.floatTolerance > 0 || *f.floatRelTolerance > 0 {
+		opts = append(opts, yamldiff.FloatTolerance(*f.floatTolerance, *f.floatRelTolerance))
+	}
+	if *f.coerceTypes {
+		opts = append(opts, yamldiff.CoerceTypes())
+	}
+	if *f.reportCoerced {
+		opts = append(opts, yamldiff.ReportCoercedTypes())
+	}
+	if *f.similarity > 0 {
+		opts = append(opts, yamldiff.SimilarityThreshold(*f.similarity))
+	}
+	if len(*f.ignore) > 0 {
+		opts = append(opts, yamldiff.IgnorePaths(*f.ignore...))
+	}
+	if len(*f.pairBy) > 0 {
+		opts = append(opts, yamldiff.PairDocumentsBy(yamldiff.DocumentKeyByPaths(*f.pairBy...)))
+	}
+	for _, m := range *f.matchArrayBy {
+		path, key, ok := strings.Cut(m, "=")
+		if !ok {
+			fmt.Fprintf(os.Stderr, "invalid --match-array-by: %s, it must be path=key\n", m)
+			os.Exit(1)
+		}
+
+		opts = append(opts, yamldiff.MatchArrayBy(path, key))
+	}
+
+	return opts
+}
+
+func (f *doFlags) load(file string) yamldiff.RawYamlList {
+	yamls, err := yamldiff.Load(load(file))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%+v", err)
+	}
+
+	if *f.selectPath != "" {
+		yamls, err = yamldiff.Select(yamls, *f.selectPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%+v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	return yamls
+}
+
 func main() {
-	ignoreEmptyFields := flag.Bool("ignore-empty-fields", false, "Ignore empty field")
-	ignoreZeroFields := flag.Bool("ignore-zero-fields", false, "Ignore zero field")
+	// a file named merge is given as ./merge to diff it
+	if len(os.Args) > 1 && os.Args[1] == "merge" {
+		runMerge(os.Args[2:])
+
+		return
+	}
+
+	df := newDoFlags(flag.CommandLine)
+	output := flag.String("output", "text", "Output format: text, json, json-patch, merge-patch or merge-patch-json")
+	highlight := flag.String("highlight", "none", "Highlight changed characters of modified values: none, ansi or markers")
+	color := flag.String("color", "auto", "Colorize the text output: auto, always or never")
+	context := flag.Int("context", -1, "Print only changes and the number of unchanged siblings around them, -1 prints everything")
+	sideBySide := flag.Bool("side-by-side", false, "Print A and B in two columns")
+	width := flag.Int("width", 160, "Total width of --side-by-side output")
+	yamlScalars := flag.Bool("yaml-scalars", false, "Print values with YAML scalar encoding instead of Go syntax")
+	originalSource := flag.Bool("original-source", false, "Print values with the original source text, keeping comments and styles")
 	flag.Parse()
 
+	dumpOpts := []yamldiff.DumpOptionFunc{}
+	if useColor(*color) {
+		dumpOpts = append(dumpOpts, yamldiff.Colorize())
+	}
+	if *context >= 0 {
+		dumpOpts = append(dumpOpts, yamldiff.Context(*context))
+	}
+	if *yamlScalars {
+		dumpOpts = append(dumpOpts, yamldiff.YAMLScalars())
+	}
+	if *originalSource {
+		dumpOpts = append(dumpOpts, yamldiff.OriginalSource())
+	}
+	if *sideBySide {
+		dumpOpts = append(dumpOpts, yamldiff.SideBySide(*width))
+	}
+	switch *highlight {
+	case "none":
+	case "ansi":
+		dumpOpts = append(dumpOpts, yamldiff.IntralineHighlight(yamldiff.HighlightANSI))
+	case "markers":
+		dumpOpts = append(dumpOpts, yamldiff.IntralineHighlight(yamldiff.HighlightMarkers))
+	default:
+		fmt.Fprintf(os.Stderr, "unknown highlight: %s\n", *highlight)
+		os.Exit(1)
+	}
+
 	args := flag.Args()
 	if len(args) != 2 {
 		fmt.Println("Usage: yaml-diff file1 file2")
+		fmt.Println("       yaml-diff merge base ours theirs")
+		fmt.Println("A file named merge can be given as ./merge")
 		os.Exit(1)
 	}
 	file1 := args[0]
 	file2 := args[1]
 
-	yamls1, err := yamldiff.Load(load(file1))
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "%+v", err)
+	diffs := yamldiff.Do(df.load(file1), df.load(file2), df.options()...)
+
+	switch *output {
+	case "text":
+		fmt.Printf("--- %s\n+++ %s\n\n", file1, file2)
+		for _, diff := range diffs {
+			fmt.Println(diff.DumpWith(dumpOpts...))
+		}
+	case "json":
+		if err := yamldiff.EncodeJSON(os.Stdout, diffs); err != nil {
+			fmt.Fprintf(os.Stderr, "%+v", err)
+			os.Exit(1)
+		}
+	case "json-patch":
+		if err := yamldiff.EncodeJSONPatch(os.Stdout, diffs); err != nil {
+			fmt.Fprintf(os.Stderr, "%+v", err)
+			os.Exit(1)
+		}
+	case "merge-patch":
+		if err := yamldiff.EncodeMergePatchYAML(os.Stdout, diffs); err != nil {
+			fmt.Fprintf(os.Stderr, "%+v", err)
+			os.Exit(1)
+		}
+	case "merge-patch-json":
+		if err := yamldiff.EncodeMergePatchJSON(os.Stdout, diffs); err != nil {
+			fmt.Fprintf(os.Stderr, "%+v", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown output format: %s\n", *output)
+		os.Exit(1)
 	}
 
-	yamls2, err := yamldiff.Load(load(file2))
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "%+v", err)
+	fmt.Print()
+}
+
+// useColor checks the color mode, auto mode respects NO_COLOR and colorizes only for terminal.
+func useColor(mode string) bool {
+	switch mode {
+	case "always":
+		return true
+	case "never":
+		return false
+	case "auto":
+	default:
+		fmt.Fprintf(os.Stderr, "unknown color mode: %s\n", mode)
+		os.Exit(1)
 	}
 
-	opts := []yamldiff.DoOptionFunc{}
-	if *ignoreEmptyFields {
-		opts = append(opts, yamldiff.EmptyAsNull())
+	if os.Getenv("NO_COLOR") != "" {
+		return false
 	}
-	if *ignoreZeroFields {
-		opts = append(opts, yamldiff.ZeroAsNull())
+
+	info, err := os.Stdout.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func runMerge(args []string) {
+	fs := flag.NewFlagSet("merge", flag.ExitOnError)
+	df := newDoFlags(fs)
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 3 {
+		fmt.Println("Usage: yaml-diff merge base ours theirs")
+		os.Exit(1)
 	}
 
-	fmt.Printf("--- %s\n+++ %s\n\n", file1, file2)
-	for _, diff := range yamldiff.Do(yamls1, yamls2, opts...) {
-		fmt.Println(diff.Dump())
+	// merging only the subtree would drop the rest of the documents from the output
+	if *df.selectPath != "" {
+		fmt.Fprintln(os.Stderr, "--select is not supported by merge")
+		os.Exit(1)
 	}
 
-	fmt.Print()
+	yamls := make([]yamldiff.RawYamlList, 0, 3)
+	for _, f := range fs.Args() {
+		y, err := yamldiff.Load(load(f))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%+v", err)
+			os.Exit(1)
+		}
+
+		yamls = append(yamls, y)
+	}
+
+	merged, conflicts := yamldiff.Merge(yamls[0], yamls[1], yamls[2], df.options()...)
+
+	out, err := yamldiff.Marshal(merged)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%+v", err)
+		os.Exit(1)
+	}
+
+	fmt.Print(out)
+
+	if len(conflicts) == 0 {
+		return
+	}
+
+	for _, c := range conflicts {
+		fmt.Fprintf(os.Stderr, "conflict: document %d, path %q (ours is taken)\n", c.Document, c.Path.String())
+	}
+	os.Exit(1)
 }
 
 func load(f string) string {
@@ -0,0 +1,271 @@
+package yamldiff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+var (
+	ErrUnsupportedPatchOp = errors.New("yamldiff: unsupported patch operation")
+	ErrInvalidPatchPath   = errors.New("yamldiff: invalid patch path")
+)
+
+// Apply replays diffs, the result of Do, onto the documents.
+// See ApplyPatches for how documents and diffs are paired.
+func Apply(raws RawYamlList, diffs []*YamlDiff) (RawYamlList, error) {
+	patches := make([][]*PatchOperation, 0, len(diffs))
+	for _, d := range diffs {
+		patches = append(patches, d.JSONPatch())
+	}
+
+	return ApplyPatches(raws, patches)
+}
+
+// ApplyPatches applies each patch to the document at the same position.
+// It matches the order of Do result: documents in A come first, then documents only in B.
+// Patches beyond the documents are applied to empty documents to add them,
+// documents whose root path is removed are dropped.
+func ApplyPatches(raws RawYamlList, patches [][]*PatchOperation) (RawYamlList, error) {
+	results := make(RawYamlList, 0, len(raws))
+
+	for i := 0; i < len(raws) || i < len(patches); i++ {
+		var raw *RawYaml
+		if i < len(raws) {
+			raw = raws[i]
+		}
+
+		if i >= len(patches) {
+			results = append(results, raw)
+
+			continue
+		}
+
+		patched, err := ApplyPatch(raw, patches[i])
+		if err != nil {
+			return nil, err
+		}
+
+		if patched != nil {
+			results = append(results, patched)
+		}
+	}
+
+	return results, nil
+}
+
+// ApplyPatch applies JSON Patch operations to the document and returns the patched copy.
+// raw can be nil for a document which doesn't exist yet, and the result is nil if the document is removed.
+func ApplyPatch(raw *RawYaml, ops []*PatchOperation) (*RawYaml, error) {
+	var v interface{} = missingKey
+	if raw != nil {
+		v = raw.raw
+	}
+
+	for _, op := range ops {
+		tokens, err := parseJSONPointer(op.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err = patchValue(v, tokens, op)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if v == missingKey {
+		return nil, nil //nolint:nilnil
+	}
+
+	return newRawYaml(v), nil
+}
+
+func patchValue(v interface{}, tokens []string, op *PatchOperation) (interface{}, error) {
+	if len(tokens) == 0 {
+		switch op.Op {
+		case PatchOpAdd, PatchOpReplace:
+			return op.Value, nil
+		case PatchOpRemove:
+			return missingKey, nil
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedPatchOp, op.Op)
+	}
+
+	if m, ok := tryMap(v); ok {
+		return patchMap(m, tokens, op)
+	}
+
+	if a, ok := tryArray(v); ok {
+		return patchArray(a, tokens, op)
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrInvalidPatchPath, op.Path)
+}
+
+func patchMap(m rawTypeMap, tokens []string, op *PatchOperation) (interface{}, error) {
+	found := -1
+	for i, item := range m {
+		if k, ok := item.Key.(string); ok && k == tokens[0] {
+			found = i
+
+			break
+		}
+	}
+
+	result := make(rawTypeMap, len(m))
+	copy(result, m)
+
+	if len(tokens) > 1 || (found >= 0 && op.Op != PatchOpRemove) {
+		if found < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPatchPath, op.Path)
+		}
+
+		child, err := patchValue(m[found].Value, tokens[1:], op)
+		if err != nil {
+			return nil, err
+		}
+
+		result[found] = yaml.MapItem{Key: m[found].Key, Value: child}
+
+		return result, nil
+	}
+
+	switch op.Op {
+	case PatchOpAdd:
+		return append(result, yaml.MapItem{Key: tokens[0], Value: op.Value}), nil
+	case PatchOpRemove:
+		if found < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPatchPath, op.Path)
+		}
+
+		return append(result[:found], result[found+1:]...), nil
+	case PatchOpReplace:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPatchPath, op.Path)
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedPatchOp, op.Op)
+}
+
+func patchArray(a rawTypeArray, tokens []string, op *PatchOperation) (interface{}, error) {
+	index := len(a)
+	if tokens[0] != "-" {
+		i, err := strconv.Atoi(tokens[0])
+		if err != nil || i < 0 || i > len(a) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPatchPath, op.Path)
+		}
+
+		index = i
+	}
+
+	result := make(rawTypeArray, 0, len(a)+1)
+	result = append(result, a[:index]...)
+
+	if len(tokens) == 1 && op.Op == PatchOpAdd {
+		result = append(result, op.Value)
+		result = append(result, a[index:]...)
+
+		return result, nil
+	}
+
+	if index >= len(a) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPatchPath, op.Path)
+	}
+
+	child, err := patchValue(a[index], tokens[1:], op)
+	if err != nil {
+		return nil, err
+	}
+
+	if child != missingKey {
+		result = append(result, child)
+	}
+	result = append(result, a[index+1:]...)
+
+	return result, nil
+}
+
+func parseJSONPointer(s string) ([]string, error) {
+	if s == "" {
+		return []string{}, nil
+	}
+
+	if !strings.HasPrefix(s, "/") {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPatchPath, s)
+	}
+
+	tokens := strings.Split(s[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = jsonPointerUnescaper.Replace(t)
+	}
+
+	return tokens, nil
+}
+
+//nolint:gochecknoglobals
+var jsonPointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+func (o *PatchOperation) UnmarshalJSON(b []byte) error {
+	var in struct {
+		Op    string          `json:"op"`
+		Path  string          `json:"path"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(b, &in); err != nil {
+		return fmt.Errorf("yamldiff: failed to decode json patch: %w", err)
+	}
+
+	o.Op = in.Op
+	o.Path = in.Path
+	o.Value = nil
+
+	// json is yaml, decode value as yaml to keep map fields order and number types same as Load
+	if len(in.Value) > 0 {
+		if err := yaml.UnmarshalWithOptions(in.Value, &o.Value, yaml.UseOrderedMap()); err != nil {
+			return fmt.Errorf("yamldiff: failed to decode json patch value: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// DecodeJSONPatch reads JSON Patch lines written by EncodeJSONPatch.
+func DecodeJSONPatch(r io.Reader) ([][]*PatchOperation, error) {
+	dec := json.NewDecoder(r)
+	patches := [][]*PatchOperation{}
+
+	for {
+		var ops []*PatchOperation
+		if err := dec.Decode(&ops); err != nil {
+			if errors.Is(err, io.EOF) {
+				return patches, nil
+			}
+
+			return nil, fmt.Errorf("yamldiff: failed to decode json patch: %w", err)
+		}
+
+		patches = append(patches, ops)
+	}
+}
+
+// Marshal encodes documents into `---` separated yaml, which can be read by Load.
+func Marshal(raws RawYamlList) (string, error) {
+	docs := make([]string, 0, len(raws))
+
+	for _, r := range raws {
+		b, err := yaml.Marshal(r.raw)
+		if err != nil {
+			return "", fmt.Errorf("yamldiff: failed to marshal yaml: %w", err)
+		}
+
+		docs = append(docs, string(b))
+	}
+
+	return strings.Join(docs, "---\n"), nil
+}
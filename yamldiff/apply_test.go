@@ -0,0 +1,154 @@
+package yamldiff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply(t *testing.T) {
+	yamlA := `
+apiVersion: v1
+kind: Service
+metadata:
+  name: my-service
+spec:
+  ports:
+  - protocol: TCP
+    port: 80
+  - protocol: UDP
+    port: 53
+---
+foo: missing-in-b
+---
+list: [1, 2, 3]
+order: [a, b, c]
+nested:
+  removed: true
+  "a/b~c": 1
+`
+	yamlB := `
+apiVersion: v1
+kind: Service
+metadata:
+  name: my-service
+  labels:
+    app: MyApp
+spec:
+  ports:
+  - protocol: TCP
+    port: 8080
+---
+list: [3, 1, 4]
+order: [b, c, a]
+nested:
+  "a/b~c": 2
+---
+bar: missing-in-a
+`
+
+	rawA, err := Load(yamlA)
+	require.NoError(t, err)
+
+	rawB, err := Load(yamlB)
+	require.NoError(t, err)
+
+	got, err := Apply(rawA, Do(rawA, rawB))
+	require.NoError(t, err)
+	require.Len(t, got, len(rawB))
+
+	for _, d := range Do(got, rawB) {
+		assert.Equal(t, DiffStatusSame, d.Status(), d.Dump())
+	}
+
+	// input must not be changed
+	for _, d := range Do(rawA, mustLoad(t, yamlA)) {
+		assert.Equal(t, DiffStatusSame, d.Status(), d.Dump())
+	}
+}
+
+func TestApplyPatches_serialized(t *testing.T) {
+	rawA, err := Load("foo: bar\nlist:\n- a: 1\n---\nremoved: true\n")
+	require.NoError(t, err)
+
+	rawB, err := Load("foo: baz\nlist:\n- a: 1\n- b: 2\n  c: 3\n")
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, EncodeJSONPatch(&b, Do(rawA, rawB)))
+
+	patches, err := DecodeJSONPatch(&b)
+	require.NoError(t, err)
+	require.Len(t, patches, 2)
+
+	got, err := ApplyPatches(rawA, patches)
+	require.NoError(t, err)
+
+	out, err := Marshal(got)
+	require.NoError(t, err)
+	assert.Equal(t, "foo: baz\nlist:\n- a: 1\n- b: 2\n  c: 3\n", out)
+}
+
+func TestApplyPatch_errors(t *testing.T) {
+	raw, err := Load("foo: bar\nlist: [1]\n")
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		op   *PatchOperation
+		want error
+	}{
+		"unsupported op": {
+			op:   &PatchOperation{Op: "move", Path: "/foo"},
+			want: ErrUnsupportedPatchOp,
+		},
+		"invalid pointer": {
+			op:   &PatchOperation{Op: PatchOpRemove, Path: "foo"},
+			want: ErrInvalidPatchPath,
+		},
+		"replace missing key": {
+			op:   &PatchOperation{Op: PatchOpReplace, Path: "/baz", Value: 1},
+			want: ErrInvalidPatchPath,
+		},
+		"remove out of range": {
+			op:   &PatchOperation{Op: PatchOpRemove, Path: "/list/1"},
+			want: ErrInvalidPatchPath,
+		},
+		"path into scalar": {
+			op:   &PatchOperation{Op: PatchOpAdd, Path: "/foo/bar", Value: 1},
+			want: ErrInvalidPatchPath,
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			_, err := ApplyPatch(raw[0], []*PatchOperation{tc.op})
+			assert.ErrorIs(t, err, tc.want)
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	raw, err := Load("b: 1\na:\n- x\n---\nc: true\n")
+	require.NoError(t, err)
+
+	got, err := Marshal(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "b: 1\na:\n- x\n---\nc: true\n", got)
+
+	reloaded, err := Load(got)
+	require.NoError(t, err)
+
+	for _, d := range Do(raw, reloaded) {
+		assert.Equal(t, DiffStatusSame, d.Status())
+	}
+}
+
+func mustLoad(t *testing.T, s string) RawYamlList {
+	t.Helper()
+
+	raw, err := Load(s)
+	require.NoError(t, err)
+
+	return raw
+}
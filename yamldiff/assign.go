@@ -0,0 +1,137 @@
+package yamldiff
+
+import "math"
+
+// assign solves the assignment problem of n rows and m columns with the Hungarian method.
+// It returns the column assigned to each row, -1 if the row is not assigned because of n > m.
+// The result is deterministic for the same costs.
+func assign(n int, m int, cost func(i int, j int) int64) []int {
+	if n > m {
+		rows := assign(m, n, func(i int, j int) int64 { return cost(j, i) })
+
+		cols := make([]int, n)
+		for i := range cols {
+			cols[i] = -1
+		}
+		for j, i := range rows {
+			cols[i] = j
+		}
+
+		return cols
+	}
+
+	// 1-indexed potentials and matching, p[j] is the row matched to column j
+	u := make([]int64, n+1)
+	v := make([]int64, m+1)
+	p := make([]int, m+1)
+	way := make([]int, m+1)
+
+	for i := 1; i <= n; i++ {
+		p[0] = i
+		j0 := 0
+		minv := make([]int64, m+1)
+		used := make([]bool, m+1)
+		for j := range minv {
+			minv[j] = math.MaxInt64
+		}
+
+		for p[j0] != 0 {
+			used[j0] = true
+			i0 := p[j0]
+			delta := int64(math.MaxInt64)
+			j1 := 0
+
+			for j := 1; j <= m; j++ {
+				if used[j] {
+					continue
+				}
+
+				cur := cost(i0-1, j-1) - u[i0] - v[j]
+				if cur < minv[j] {
+					minv[j] = cur
+					way[j] = j0
+				}
+				if minv[j] < delta {
+					delta = minv[j]
+					j1 = j
+				}
+			}
+
+			for j := 0; j <= m; j++ {
+				if used[j] {
+					u[p[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+
+			j0 = j1
+		}
+
+		for j0 != 0 {
+			j1 := way[j0]
+			p[j0] = p[j1]
+			j0 = j1
+		}
+	}
+
+	cols := make([]int, n)
+	for j := 1; j <= m; j++ {
+		if p[j] != 0 {
+			cols[p[j]-1] = j - 1
+		}
+	}
+
+	return cols
+}
+
+// minCostPairs pairs as many elements as possible with the minimum total cost, and returns index pairs in the order of i.
+// The cost returns false if the elements can't be paired.
+// If total costs are the same, pairs of closer positions are preferred.
+func minCostPairs(n int, m int, cost func(i int, j int) (int, bool)) [][2]int {
+	// the tie-break never exceeds one unit of cost
+	scale := int64(n+1) * int64(m+1)
+
+	costs := make([][]int64, n)
+	forbidden := int64(1)
+	for i := range costs {
+		costs[i] = make([]int64, m)
+		for j := range costs[i] {
+			c, ok := cost(i, j)
+			if !ok {
+				costs[i][j] = -1
+
+				continue
+			}
+
+			costs[i][j] = int64(c)*scale + int64(abs(i-j))
+			forbidden += costs[i][j]
+		}
+	}
+
+	cols := assign(n, m, func(i int, j int) int64 {
+		if costs[i][j] < 0 {
+			return forbidden
+		}
+
+		return costs[i][j]
+	})
+
+	pairs := [][2]int{}
+	for i, j := range cols {
+		if j >= 0 && costs[i][j] >= 0 {
+			pairs = append(pairs, [2]int{i, j})
+		}
+	}
+
+	return pairs
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
@@ -0,0 +1,63 @@
+package yamldiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_assign(t *testing.T) {
+	tests := map[string]struct {
+		cost [][]int64
+		want []int
+	}{
+		"square": {
+			cost: [][]int64{
+				{1, 2},
+				{2, 4},
+			},
+			want: []int{1, 0},
+		},
+		"more columns": {
+			cost: [][]int64{
+				{5, 1, 3},
+				{5, 2, 9},
+			},
+			want: []int{2, 1},
+		},
+		"more rows": {
+			cost: [][]int64{
+				{5, 1},
+				{1, 5},
+				{0, 3},
+			},
+			want: []int{1, -1, 0},
+		},
+		"negative": {
+			cost: [][]int64{
+				{-10, 0},
+				{0, 1},
+			},
+			want: []int{0, 1},
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			got := assign(len(tc.cost), len(tc.cost[0]), func(i int, j int) int64 { return tc.cost[i][j] })
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func Test_minCostPairs(t *testing.T) {
+	// all costs are the same, so the same positions are paired
+	got := minCostPairs(3, 2, func(int, int) (int, bool) { return 1, true })
+	assert.Equal(t, [][2]int{{0, 0}, {1, 1}}, got)
+
+	got = minCostPairs(0, 2, func(int, int) (int, bool) { return 1, true })
+	assert.Equal(t, [][2]int{}, got)
+
+	// pairs which can't be paired are left
+	got = minCostPairs(2, 2, func(i int, j int) (int, bool) { return 1, i == 1 })
+	assert.Equal(t, [][2]int{{1, 1}}, got)
+}
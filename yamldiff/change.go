@@ -0,0 +1,129 @@
+package yamldiff
+
+type ChangeKind int
+
+const (
+	ChangeKindAdded       ChangeKind = 1
+	ChangeKindRemoved     ChangeKind = 2
+	ChangeKindModified    ChangeKind = 3
+	ChangeKindTypeChanged ChangeKind = 4
+	ChangeKindMoved       ChangeKind = 5
+	ChangeKindCoerced     ChangeKind = 6 // the same value in the different type, reported by ReportCoercedTypes
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeKindAdded:
+		return "added"
+	case ChangeKindRemoved:
+		return "removed"
+	case ChangeKindModified:
+		return "modified"
+	case ChangeKindTypeChanged:
+		return "type-changed"
+	case ChangeKindMoved:
+		return "moved"
+	case ChangeKindCoerced:
+		return "coerced"
+	}
+
+	return "unknown"
+}
+
+// Change is a leaf difference of the diff tree.
+// IndexA and IndexB are positions of array element, -1 if it's missing or not an array element.
+// Path addresses array elements by the position in A, or by the position in B for elements only in B.
+// Hunks is line-level diff if the modified values are multi-line strings.
+type Change struct {
+	Path   Path
+	Kind   ChangeKind
+	A      interface{}
+	B      interface{}
+	IndexA int
+	IndexB int
+	Hunks  []*Hunk
+}
+
+// Changes returns the leaf differences in the same order as Walk visits them.
+// Moved array elements and coerced scalars are also reported even if the values are the same.
+func (y *YamlDiff) Changes() []*Change {
+	changes := []*Change{}
+
+	y.Walk(func(n *Node) bool {
+		if n.Status != DiffStatusMoved && len(n.Children) > 0 {
+			return true
+		}
+
+		if n.Status == DiffStatusSame && !n.TypeChanged {
+			return false
+		}
+
+		c := &Change{
+			Path:   n.Path,
+			Kind:   changeKind(n),
+			A:      n.A,
+			B:      n.B,
+			IndexA: n.IndexA,
+			IndexB: n.IndexB,
+		}
+		if c.Kind == ChangeKindModified {
+			c.Hunks = lineHunks(c.A, c.B)
+		}
+
+		changes = append(changes, c)
+
+		return false
+	})
+
+	return changes
+}
+
+func changeKind(n *Node) ChangeKind {
+	switch n.Status {
+	case DiffStatusSame:
+		return ChangeKindCoerced
+	case DiffStatus1Missing:
+		return ChangeKindAdded
+	case DiffStatus2Missing:
+		return ChangeKindRemoved
+	case DiffStatusMoved:
+		return ChangeKindMoved
+	}
+
+	if valueType(n.A) != valueType(n.B) {
+		return ChangeKindTypeChanged
+	}
+
+	return ChangeKindModified
+}
+
+type valueTypeKind int
+
+const (
+	valueTypeNull valueTypeKind = iota
+	valueTypeBool
+	valueTypeNumber
+	valueTypeString
+	valueTypeMap
+	valueTypeArray
+	valueTypeOther
+)
+
+func valueType(v interface{}) valueTypeKind {
+	switch v.(type) {
+	case nil, _missingKey:
+		return valueTypeNull
+	case bool:
+		return valueTypeBool
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return valueTypeNumber
+	case string:
+		return valueTypeString
+	case rawTypeMap:
+		return valueTypeMap
+	case rawTypeArray:
+		return valueTypeArray
+	}
+
+	return valueTypeOther
+}
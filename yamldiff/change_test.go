@@ -0,0 +1,189 @@
+package yamldiff
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYamlDiff_Changes(t *testing.T) {
+	yamlA, err := Load(`
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: app
+        image: my-app:1.0.0
+  port: "80"
+removed: foo
+`)
+	require.NoError(t, err)
+
+	yamlB, err := Load(`
+spec:
+  replicas: 10
+  template:
+    spec:
+      containers:
+      - name: app
+        image: my-app:1.1.0
+  port: 80
+added:
+  foo: bar
+`)
+	require.NoError(t, err)
+
+	diffs := Do(yamlA, yamlB)
+	require.Len(t, diffs, 1)
+
+	got := diffs[0].Changes()
+
+	assert.Equal(t, []*Change{
+		{
+			Path:   Path{}.appendKey("spec").appendKey("replicas"),
+			Kind:   ChangeKindModified,
+			A:      uint64(3),
+			B:      uint64(10),
+			IndexA: -1,
+			IndexB: -1,
+		},
+		{
+			Path:   Path{}.appendKey("spec").appendKey("template").appendKey("spec").appendKey("containers").appendIndex(0).appendKey("image"),
+			Kind:   ChangeKindModified,
+			A:      "my-app:1.0.0",
+			B:      "my-app:1.1.0",
+			IndexA: -1,
+			IndexB: -1,
+		},
+		{
+			Path:   Path{}.appendKey("spec").appendKey("port"),
+			Kind:   ChangeKindTypeChanged,
+			A:      "80",
+			B:      uint64(80),
+			IndexA: -1,
+			IndexB: -1,
+		},
+		{
+			Path:   Path{}.appendKey("removed"),
+			Kind:   ChangeKindRemoved,
+			A:      "foo",
+			IndexA: -1,
+			IndexB: -1,
+		},
+		{
+			Path:   Path{}.appendKey("added"),
+			Kind:   ChangeKindAdded,
+			B:      rawTypeMap{{Key: "foo", Value: "bar"}},
+			IndexA: -1,
+			IndexB: -1,
+		},
+	}, got)
+	assert.Equal(t, "spec.template.spec.containers[0].image", got[1].Path.String())
+}
+
+func TestYamlDiff_Changes_same(t *testing.T) {
+	yamlA, err := Load("foo: bar\n")
+	require.NoError(t, err)
+
+	assert.Empty(t, Do(yamlA, yamlA)[0].Changes())
+}
+
+func TestYamlDiff_Changes_moved(t *testing.T) {
+	yamlA := mustLoad(t, "list: [a, b, c]\n")
+	yamlB := mustLoad(t, "list: [c, a, b]\n")
+
+	for _, opts := range [][]DoOptionFunc{{}, {OrderedArrays()}} {
+		diffs := Do(yamlA, yamlB, opts...)
+		require.Len(t, diffs, 1)
+
+		assert.Equal(t, []*Change{
+			{
+				Path:   Path{}.appendKey("list").appendIndex(2),
+				Kind:   ChangeKindMoved,
+				A:      "c",
+				B:      "c",
+				IndexA: 2,
+				IndexB: 0,
+			},
+		}, diffs[0].Changes())
+	}
+
+	// unordered array has the same content, but the document tells the elements are moved
+	assert.Equal(t, DiffStatusMoved, Do(yamlA, yamlB)[0].Status())
+	assert.Equal(t, DiffStatusSame, Do(yamlA, yamlB)[0].Tree().Children[0].Status)
+	assert.Equal(t, DiffStatusDiff, Do(yamlA, yamlB, OrderedArrays())[0].Status())
+}
+
+func TestYamlDiff_Changes_movedWithModified(t *testing.T) {
+	yamlA := mustLoad(t, "list:\n- {name: x, v: 1}\n- {name: y}\n")
+	yamlB := mustLoad(t, "list:\n- {name: y}\n- {name: x, v: 2}\n")
+
+	got := []string{}
+	for _, c := range Do(yamlA, yamlB)[0].Changes() {
+		got = append(got, fmt.Sprintf("%s %s", c.Path, c.Kind))
+	}
+
+	// the modified element can't be reported as moved, so the same element is
+	assert.Equal(t, []string{"list[1] moved", "list[0].v modified"}, got)
+}
+
+func TestYamlDiff_Changes_hunks(t *testing.T) {
+	diffs := Do(mustLoad(t, "script: |\n  a\n  b\nname: foo\n"), mustLoad(t, "script: \"\"\nname: bar\n"))
+	require.Len(t, diffs, 1)
+
+	changes := map[string]*Change{}
+	for _, c := range diffs[0].Changes() {
+		changes[c.Path.String()] = c
+	}
+	require.Len(t, changes, 2)
+
+	assert.Nil(t, changes["name"].Hunks)
+	assert.Equal(t, []*Hunk{
+		{
+			StartA: 1,
+			LinesA: 2,
+			StartB: 1,
+			LinesB: 0,
+			Lines:  []*Line{{Op: LineOpDelete, Text: "a"}, {Op: LineOpDelete, Text: "b"}},
+		},
+	}, changes["script"].Hunks)
+	assert.Equal(t, "@@ -1,2 +0,0 @@", changes["script"].Hunks[0].Header())
+}
+
+func TestYamlDiff_Changes_coerced(t *testing.T) {
+	yamlA := mustLoad(t, "port: \"8080\"\nenabled: true\nname: foo")
+	yamlB := mustLoad(t, "port: 8080\nenabled: \"true\"\nname: bar")
+
+	tests := map[string]struct {
+		opts []DoOptionFunc
+		want []ChangeKind
+	}{
+		"default": {
+			want: []ChangeKind{ChangeKindTypeChanged, ChangeKindTypeChanged, ChangeKindModified},
+		},
+		"coerce": {
+			opts: []DoOptionFunc{CoerceTypes()},
+			want: []ChangeKind{ChangeKindModified},
+		},
+		"report": {
+			opts: []DoOptionFunc{ReportCoercedTypes()},
+			want: []ChangeKind{ChangeKindCoerced, ChangeKindCoerced, ChangeKindModified},
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			diffs := Do(yamlA, yamlB, tc.opts...)
+			require.Len(t, diffs, 1)
+
+			got := []ChangeKind{}
+			for _, c := range diffs[0].Changes() {
+				got = append(got, c.Kind)
+			}
+
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
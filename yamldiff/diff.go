@@ -3,6 +3,7 @@ package yamldiff
 import (
 	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/goccy/go-yaml"
 )
@@ -14,10 +15,28 @@ const (
 	DiffStatusDiff     DiffStatus = 2
 	DiffStatus1Missing DiffStatus = 3
 	DiffStatus2Missing DiffStatus = 4
+	DiffStatusMoved    DiffStatus = 5 // array element is the same but at a different position
 
 	fakeForMissingKey = "000_unexpected-key_000"
 )
 
+func (s DiffStatus) String() string {
+	switch s {
+	case DiffStatusSame:
+		return "same"
+	case DiffStatusDiff:
+		return "diff"
+	case DiffStatus1Missing:
+		return "missing-in-a"
+	case DiffStatus2Missing:
+		return "missing-in-b"
+	case DiffStatusMoved:
+		return "moved"
+	}
+
+	return "unknown"
+}
+
 type (
 	rawType      = interface{}
 	rawTypeMap   = yaml.MapSlice
@@ -33,6 +52,22 @@ type (
 		status    DiffStatus
 		diffCount int
 		treeLevel int
+
+		// position of array element, -1 if it's missing in A or B
+		indexA int
+		indexB int
+
+		// excluded from the result by IgnorePaths
+		ignored bool
+
+		// the same after type coercion, and it should be reported
+		typeChanged bool
+
+		// some array elements in the descendants are moved
+		hasMoved bool
+
+		// some scalars in the descendants are coerced, and they should be reported
+		hasCoerced bool
 	}
 
 	diffChildrenArray = []*diff
@@ -112,21 +147,14 @@ func (r *runner) handleMap(rawA rawType, rawB rawType, level int) *diff {
 				continue
 			}
 
-			result.children.m[keyA] = r.performDiff(valA.Value, valB.Value, level+1)
-			if result.children.m[keyA].status != DiffStatusSame {
-				result.status = DiffStatusDiff // top level diff can't specify actual reason
-			}
-
+			result.setMapChild(keyA, r.performChildDiff(PathElement{Key: keyA}, valA.Value, valB.Value, level+1))
 			foundKey = true
 
 			break
 		}
 
 		if !foundKey {
-			result.children.m[keyA] = r.performDiff(valA.Value, missingKey, level+1)
-			if result.children.m[keyA].status != DiffStatusSame {
-				result.status = DiffStatusDiff // top level diff can't specify actual reason
-			}
+			result.setMapChild(keyA, r.performChildDiff(PathElement{Key: keyA}, valA.Value, missingKey, level+1))
 		}
 	}
 
@@ -154,10 +182,7 @@ func (r *runner) handleMap(rawA rawType, rawB rawType, level int) *diff {
 		}
 
 		if !foundKey {
-			result.children.m[keyB] = r.performDiff(missingKey, valB.Value, level+1)
-			if result.children.m[keyB].status != DiffStatusSame {
-				result.status = DiffStatusDiff // top level diff can't specify actual reason
-			}
+			result.setMapChild(keyB, r.performChildDiff(PathElement{Key: keyB}, missingKey, valB.Value, level+1))
 		}
 	}
 
@@ -170,6 +195,35 @@ func (r *runner) handleMap(rawA rawType, rawB rawType, level int) *diff {
 	return result
 }
 
+// setMapChild stores the child of map, ignored children are dropped.
+func (d *diff) setMapChild(key string, child *diff) {
+	if child.ignored {
+		return
+	}
+
+	d.children.m[key] = child
+	if child.status != DiffStatusSame {
+		d.status = DiffStatusDiff // top level diff can't specify actual reason
+	}
+	d.inherit(child)
+}
+
+// inherit tells the parent that the child is moved or coerced, or has such descendants,
+// because the status of the parent can still be the same.
+func (d *diff) inherit(child *diff) {
+	if child.status == DiffStatusMoved || child.hasMoved {
+		d.hasMoved = true
+	}
+	if child.typeChanged || child.hasCoerced {
+		d.hasCoerced = true
+	}
+}
+
+// changed checks the value or its descendants have something to report.
+func (d *diff) changed() bool {
+	return d.status != DiffStatusSame || d.typeChanged || d.hasMoved || d.hasCoerced
+}
+
 func (r *runner) handleArray(rawA rawType, rawB rawType, level int) *diff {
 	result := &diff{
 		a:         rawA,
@@ -200,16 +254,34 @@ func (r *runner) handleArray(rawA rawType, rawB rawType, level int) *diff {
 	}
 	result.status = DiffStatusSame
 
-	// check each elements is same or not
-	diffs := map[string]*diff{}
+	if r.option.orderedArrays {
+		return r.handleOrderedArray(result, arrayA, arrayB, level)
+	}
+
 	foundA := map[int]struct{}{}
 	foundB := map[int]struct{}{}
 
+	// pair elements by identity key if it's specified for this array
+	if key, ok := r.arrayMatchKey(); ok {
+		result.children.a = append(result.children.a, r.pairArrayByKey(key, arrayA, arrayB, foundA, foundB, level)...)
+	}
+
+	// check each elements is same or not
+	diffs := map[string]*diff{}
+
 	for keyA, valA := range arrayA {
+		if _, ok := foundA[keyA]; ok {
+			continue
+		}
+
 		for keyB, valB := range arrayB {
+			if _, ok := foundB[keyB]; ok {
+				continue
+			}
+
 			key := fmt.Sprintf("%d-%d", keyA, keyB)
 
-			diffs[key] = r.performDiff(valA, valB, level+1)
+			diffs[key] = r.performArrayElementDiff(valA, valB, keyA, keyB, level)
 			if diffs[key].status == DiffStatusSame {
 				// store result and mark as confirmed
 				result.children.a = append(result.children.a, diffs[key])
@@ -221,80 +293,267 @@ func (r *runner) handleArray(rawA rawType, rawB rawType, level int) *diff {
 		}
 	}
 
-	// found all elements, it's same array
-	if len(foundA) == len(arrayA) && len(foundB) == len(arrayB) {
-		return result
+	// pair the rest elements with the minimum total diff
+	restA := []int{}
+	for k := range arrayA {
+		if _, ok := foundA[k]; !ok {
+			restA = append(restA, k)
+		}
 	}
 
-	result.status = DiffStatusDiff
+	restB := []int{}
+	for k := range arrayB {
+		if _, ok := foundB[k]; !ok {
+			restB = append(restB, k)
+		}
+	}
 
-	// check diff elements
-	for {
-		// all confirmed arrayA, need to consider arrayB
-		if len(foundA) == len(arrayA) {
-			for k, v := range arrayB {
-				if _, ok := foundB[k]; ok {
-					continue
-				}
+	similar := r.similarPairs(arrayA, arrayB, true)
+	pairs := minCostPairs(len(restA), len(restB), func(i int, j int) (int, bool) {
+		d := diffs[fmt.Sprintf("%d-%d", restA[i], restB[j])]
 
-				result.children.a = append(result.children.a, r.performDiff(nil, v, level+1))
-			}
+		return d.diffCount, similar(d, d.indexA, d.indexB)
+	})
 
-			break
+	for _, p := range pairs {
+		keyA, keyB := restA[p[0]], restB[p[1]]
+
+		result.children.a = append(result.children.a, diffs[fmt.Sprintf("%d-%d", keyA, keyB)])
+		foundA[keyA] = struct{}{}
+		foundB[keyB] = struct{}{}
+	}
+
+	for _, k := range restA {
+		if _, ok := foundA[k]; !ok {
+			result.children.a = append(result.children.a, r.performArrayElementDiff(arrayA[k], nil, k, -1, level))
 		}
+	}
 
-		// all confirmed arrayB, need to consider in arrayA
-		if len(foundB) == len(arrayB) {
-			for k, v := range arrayA {
-				if _, ok := foundA[k]; ok {
-					continue
-				}
+	for _, k := range restB {
+		if _, ok := foundB[k]; !ok {
+			result.children.a = append(result.children.a, r.performArrayElementDiff(nil, arrayB[k], -1, k, level))
+		}
+	}
 
-				result.children.a = append(result.children.a, r.performDiff(v, nil, level+1))
-			}
+	sum := 0
+	for _, v := range result.children.a {
+		if v.status != DiffStatusSame {
+			result.status = DiffStatusDiff
+		}
+		sum += v.diffCount
+	}
+	result.diffCount = sum
 
-			break
+	// unordered array is still the same, but tell which elements are moved
+	markMoved(result.children.a)
+	for _, v := range result.children.a {
+		result.inherit(v)
+	}
+
+	return result
+}
+
+// markMoved marks the same elements which are out of order as moved.
+// The elements in the heaviest increasing sequence of positions are treated as not moved.
+// Only the same elements can be marked, so the others are kept in the sequence first.
+func markMoved(children diffChildrenArray) {
+	paired := diffChildrenArray{}
+	for _, c := range children {
+		if c.indexA >= 0 && c.indexB >= 0 {
+			paired = append(paired, c)
 		}
+	}
 
-		smallestDiff := &diff{diffCount: 100000} // FIXME
-		smallestKeyA := 0
-		smallestKeyB := 0
+	sort.SliceStable(paired, func(i, j int) bool { return paired[i].indexA < paired[j].indexA })
 
-		for keyA := range arrayA {
-			if _, ok := foundA[keyA]; ok {
-				continue
-			}
+	markable := func(c *diff) bool {
+		return c.status == DiffStatusSame && !c.ignored
+	}
+
+	indices := make([]int, 0, len(paired))
+	weights := make([]int, 0, len(paired))
+	for _, c := range paired {
+		indices = append(indices, c.indexB)
+
+		if markable(c) {
+			weights = append(weights, 1)
+		} else {
+			weights = append(weights, len(paired)+1)
+		}
+	}
+
+	for i, kept := range heaviestIncreasing(indices, weights) {
+		if !kept && markable(paired[i]) {
+			paired[i].status = DiffStatusMoved
+		}
+	}
+}
 
-			for keyB := range arrayB {
-				if _, ok := foundB[keyB]; ok {
-					continue
-				}
-
-				key := fmt.Sprintf("%d-%d", keyA, keyB)
-				if diffs[key].status == DiffStatusSame {
-					continue // unexpected case
-				}
-
-				if smallestDiff.diffCount > diffs[key].diffCount {
-					smallestDiff = diffs[key]
-					smallestKeyA = keyA
-					smallestKeyB = keyB
-				}
+// heaviestIncreasing marks the elements of a strictly increasing subsequence with the largest sum of weights.
+func heaviestIncreasing(values []int, weights []int) []bool {
+	// sums[i] is the largest weight of increasing subsequences which end with i
+	sums := make([]int, len(values))
+	prev := make([]int, len(values))
+	last := -1
+
+	for i, v := range values {
+		sums[i] = weights[i]
+		prev[i] = -1
+
+		for j := 0; j < i; j++ {
+			if values[j] < v && sums[j]+weights[i] >= sums[i] {
+				sums[i] = sums[j] + weights[i]
+				prev[i] = j
 			}
 		}
 
-		result.children.a = append(result.children.a, smallestDiff)
-		foundA[smallestKeyA] = struct{}{}
-		foundB[smallestKeyB] = struct{}{}
+		if last < 0 || sums[i] >= sums[last] {
+			last = i
+		}
 	}
 
-	sum := 0
-	for _, v := range result.children.a {
-		sum += v.diffCount
+	kept := make([]bool, len(values))
+	for i := last; i >= 0; i = prev[i] {
+		kept[i] = true
 	}
-	result.diffCount = sum
 
-	return result
+	return kept
+}
+
+// performChildDiff tracks the path of the child while performing diff.
+// The child at ignored path is the same without comparing.
+func (r *runner) performChildDiff(e PathElement, rawA rawType, rawB rawType, level int) *diff {
+	parent := r.path
+	r.path = append(parent[:len(parent):len(parent)], e)
+
+	defer func() { r.path = parent }()
+
+	if r.ignored() {
+		return &diff{
+			a:         rawA,
+			b:         rawB,
+			status:    DiffStatusSame,
+			treeLevel: level,
+			ignored:   true,
+		}
+	}
+
+	return r.performDiff(rawA, rawB, level)
+}
+
+// withElementPath runs fn with the path of the array element.
+func (r *runner) withElementPath(index int, fn func()) {
+	parent := r.path
+	r.path = append(parent[:len(parent):len(parent)], PathElement{Index: index, IsIndex: true})
+
+	defer func() { r.path = parent }()
+
+	fn()
+}
+
+// performArrayElementDiff performs diff of array elements, index is -1 if it's missing.
+func (r *runner) performArrayElementDiff(rawA rawType, rawB rawType, indexA int, indexB int, level int) *diff {
+	index := indexA
+	if index < 0 {
+		index = indexB
+	}
+
+	d := r.performChildDiff(PathElement{Index: index, IsIndex: true}, rawA, rawB, level+1)
+	d.indexA = indexA
+	d.indexB = indexB
+
+	return d
+}
+
+func (r *runner) ignored() bool {
+	for _, p := range r.option.ignorePaths {
+		if p.match(r.path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *runner) arrayMatchKey() (string, bool) {
+	for _, m := range r.option.arrayMatchKeys {
+		if m.path.match(r.path) {
+			return m.key, true
+		}
+	}
+
+	return "", false
+}
+
+// pairArrayByKey pairs the elements which have the same value of the key.
+// The elements which have the key but can't be paired are treated as missing.
+func (r *runner) pairArrayByKey(
+	key string,
+	arrayA rawTypeArray,
+	arrayB rawTypeArray,
+	foundA map[int]struct{},
+	foundB map[int]struct{},
+	level int,
+) diffChildrenArray {
+	children := diffChildrenArray{}
+
+	idsB := map[string][]int{}
+	for keyB, valB := range arrayB {
+		if id, ok := elementID(valB, key); ok {
+			idsB[id] = append(idsB[id], keyB)
+		}
+	}
+
+	for keyA, valA := range arrayA {
+		id, ok := elementID(valA, key)
+		if !ok {
+			continue
+		}
+
+		foundA[keyA] = struct{}{}
+
+		if len(idsB[id]) == 0 {
+			children = append(children, r.performArrayElementDiff(valA, nil, keyA, -1, level))
+
+			continue
+		}
+
+		keyB := idsB[id][0]
+		idsB[id] = idsB[id][1:]
+		foundB[keyB] = struct{}{}
+
+		children = append(children, r.performArrayElementDiff(valA, arrayB[keyB], keyA, keyB, level))
+	}
+
+	for keyB, valB := range arrayB {
+		if _, ok := foundB[keyB]; ok {
+			continue
+		}
+
+		if _, ok := elementID(valB, key); !ok {
+			continue
+		}
+
+		foundB[keyB] = struct{}{}
+		children = append(children, r.performArrayElementDiff(nil, valB, -1, keyB, level))
+	}
+
+	return children
+}
+
+func elementID(v rawType, key string) (string, bool) {
+	m, ok := tryMap(v)
+	if !ok {
+		return "", false
+	}
+
+	for _, item := range m {
+		if k, ok := item.Key.(string); ok && k == key {
+			return fmt.Sprint(item.Value), true
+		}
+	}
+
+	return "", false
 }
 
 func (r *runner) handlePrimitive(rawA rawType, rawB rawType, level int) *diff {
@@ -338,42 +597,41 @@ func (r *runner) handlePrimitive(rawA rawType, rawB rawType, level int) *diff {
 	case rawB == nil:
 		result.status = DiffStatusDiff
 		result.diffCount = len(strA)
-	case rawA == rawB:
+	case rawA == rawB, r.sameNumber(rawA, rawB):
 		result.status = DiffStatusSame
+	case r.option.coerceTypes && sameCanonical(rawA, rawB):
+		result.status = DiffStatusSame
+		result.typeChanged = r.option.reportCoercedTypes
 	default:
 		result.status = DiffStatusDiff
 	}
 
 	// calculate diff size for diff
 	if result.status == DiffStatusDiff {
-		maxLen := len(strA)
-		if lenB := len(strB); maxLen < lenB {
-			maxLen = lenB
-		}
-
-		for nA, a := range strA {
-			// lenA > lenB
-			if len(strB) <= nA {
-				result.diffCount = maxLen - nA
+		result.diffCount = max(len(strA), len(strB)) - commonPrefix(strA, strB)
+	}
 
-				break
-			}
+	return result
+}
 
-			// found diff in A and B strings
-			if b := strB[nA]; a != b {
-				result.diffCount = maxLen - nA
+// commonPrefix returns the length of the common prefix.
+func commonPrefix(a []rune, b []rune) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
 
-				break
-			}
-		}
+	return n
+}
 
-		// guess lenA < lemB
-		if result.diffCount == 0 {
-			result.diffCount = maxLen - len(strA)
-		}
+// commonSuffix returns the length of the common suffix.
+func commonSuffix(a []rune, b []rune) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
 	}
 
-	return result
+	return n
 }
 
 func tryMap(x rawType) (rawTypeMap, bool) {
@@ -120,9 +120,9 @@ func Test_performDiff(t *testing.T) {
 				want: &diff{
 					children: &diffChildren{
 						a: diffChildrenArray{
-							{a: 1, b: 1, status: DiffStatusSame, treeLevel: 1},
-							{a: 2, b: 2, status: DiffStatusSame, treeLevel: 1},
-							{a: 3, b: 3, status: DiffStatusSame, treeLevel: 1},
+							{a: 1, b: 1, status: DiffStatusSame, treeLevel: 1, indexA: 0, indexB: 0},
+							{a: 2, b: 2, status: DiffStatusSame, treeLevel: 1, indexA: 1, indexB: 1},
+							{a: 3, b: 3, status: DiffStatusSame, treeLevel: 1, indexA: 2, indexB: 2},
 						},
 					},
 					status: DiffStatusSame,
@@ -134,12 +134,13 @@ func Test_performDiff(t *testing.T) {
 				want: &diff{
 					children: &diffChildren{
 						a: diffChildrenArray{
-							{a: 1, b: 1, status: DiffStatusSame, treeLevel: 1},
-							{a: 2, b: 2, status: DiffStatusSame, treeLevel: 1},
-							{a: 3, b: 3, status: DiffStatusSame, treeLevel: 1},
+							{a: 1, b: 1, status: DiffStatusSame, treeLevel: 1, indexA: 0, indexB: 1},
+							{a: 2, b: 2, status: DiffStatusSame, treeLevel: 1, indexA: 1, indexB: 2},
+							{a: 3, b: 3, status: DiffStatusMoved, treeLevel: 1, indexA: 2, indexB: 0},
 						},
 					},
-					status: DiffStatusSame,
+					status:   DiffStatusSame,
+					hasMoved: true,
 				},
 			},
 			"missing in A": {
@@ -148,9 +149,9 @@ func Test_performDiff(t *testing.T) {
 				want: &diff{
 					children: &diffChildren{
 						a: diffChildrenArray{
-							{a: 1, b: 1, status: DiffStatusSame, treeLevel: 1},
-							{a: 2, b: 2, status: DiffStatusSame, treeLevel: 1},
-							{b: 3, status: DiffStatusDiff, diffCount: 5, treeLevel: 1},
+							{a: 1, b: 1, status: DiffStatusSame, treeLevel: 1, indexA: 0, indexB: 0},
+							{a: 2, b: 2, status: DiffStatusSame, treeLevel: 1, indexA: 1, indexB: 1},
+							{b: 3, status: DiffStatusDiff, diffCount: 5, treeLevel: 1, indexA: -1, indexB: 2},
 						},
 					},
 					diffCount: 5,
@@ -163,9 +164,9 @@ func Test_performDiff(t *testing.T) {
 				want: &diff{
 					children: &diffChildren{
 						a: diffChildrenArray{
-							{a: 1, b: 1, status: DiffStatusSame, treeLevel: 1},
-							{a: 3, b: 3, status: DiffStatusSame, treeLevel: 1},
-							{a: 2, status: DiffStatusDiff, diffCount: 5, treeLevel: 1}, // missing is added by last
+							{a: 1, b: 1, status: DiffStatusSame, treeLevel: 1, indexA: 0, indexB: 0},
+							{a: 3, b: 3, status: DiffStatusSame, treeLevel: 1, indexA: 2, indexB: 1},
+							{a: 2, status: DiffStatusDiff, diffCount: 5, treeLevel: 1, indexA: 1, indexB: -1}, // missing is added by last
 						},
 					},
 					diffCount: 5,
@@ -178,13 +179,14 @@ func Test_performDiff(t *testing.T) {
 				want: &diff{
 					children: &diffChildren{
 						a: diffChildrenArray{
-							{a: 1, b: 1, status: DiffStatusSame, treeLevel: 1},
-							{a: 3, b: 3, status: DiffStatusSame, treeLevel: 1},
-							{a: 2, b: 4, status: DiffStatusDiff, diffCount: 1, treeLevel: 1}, // because can't find missing, it's diff.
+							{a: 1, b: 1, status: DiffStatusSame, treeLevel: 1, indexA: 0, indexB: 0},
+							{a: 3, b: 3, status: DiffStatusMoved, treeLevel: 1, indexA: 2, indexB: 1},              // out of order with the modified element
+							{a: 2, b: 4, status: DiffStatusDiff, diffCount: 1, treeLevel: 1, indexA: 1, indexB: 2}, // because can't find missing, it's diff.
 						},
 					},
 					diffCount: 1,
 					status:    DiffStatusDiff,
+					hasMoved:  true,
 				},
 			},
 			"complicated": {
@@ -193,20 +195,24 @@ func Test_performDiff(t *testing.T) {
 				want: &diff{
 					children: &diffChildren{
 						a: diffChildrenArray{
-							{a: 1, b: 1, status: DiffStatusSame, treeLevel: 1},
-							{a: 5, b: 5, status: DiffStatusSame, treeLevel: 1},
+							{a: 1, b: 1, status: DiffStatusSame, treeLevel: 1, indexA: 0, indexB: 0},
+							{a: 5, b: 5, status: DiffStatusSame, treeLevel: 1, indexA: 2, indexB: 1},
 							{
 								a:         6,
 								b:         rawTypeArray{2},
 								diffCount: 3,
 								status:    DiffStatusDiff,
 								treeLevel: 1,
+								indexA:    3,
+								indexB:    2,
 							},
 							{
 								a:         rawTypeArray{2, 3, 4},
 								status:    DiffStatusDiff,
 								diffCount: 7,
 								treeLevel: 1,
+								indexA:    1,
+								indexB:    -1,
 							},
 						},
 					},
@@ -782,3 +788,279 @@ func Test_performDiff_zeroAsNull(t *testing.T) {
 		})
 	}
 }
+
+func Test_performDiff_matchArrayBy(t *testing.T) {
+	yamlA := mustLoad(t, `
+spec:
+  containers:
+  - name: app-v1
+    image: img:1
+  - name: removed
+    image: img:1
+  - image: no-name
+`)
+	yamlB := mustLoad(t, `
+spec:
+  containers:
+  - name: app-v2
+    image: img:1
+  - name: app-v1
+    image: other:2
+  - image: no-name
+`)
+
+	type change struct {
+		path string
+		kind ChangeKind
+	}
+
+	tests := map[string]struct {
+		opts []DoOptionFunc
+		want []change
+	}{
+		"similarity": {
+			want: []change{
+				{path: "spec.containers[0].image", kind: ChangeKindModified},
+				{path: "spec.containers[1].name", kind: ChangeKindModified},
+			},
+		},
+		"by name": {
+			opts: []DoOptionFunc{MatchArrayBy("spec.containers", "name")},
+			want: []change{
+				{path: "spec.containers[0].image", kind: ChangeKindModified},
+				{path: "spec.containers[1]", kind: ChangeKindRemoved},
+				{path: "spec.containers[0]", kind: ChangeKindAdded},
+			},
+		},
+		"by name with wildcard": {
+			opts: []DoOptionFunc{MatchArrayBy("*.containers", "name")},
+			want: []change{
+				{path: "spec.containers[0].image", kind: ChangeKindModified},
+				{path: "spec.containers[1]", kind: ChangeKindRemoved},
+				{path: "spec.containers[0]", kind: ChangeKindAdded},
+			},
+		},
+		"other path": {
+			opts: []DoOptionFunc{MatchArrayBy("spec.volumes", "name")},
+			want: []change{
+				{path: "spec.containers[0].image", kind: ChangeKindModified},
+				{path: "spec.containers[1].name", kind: ChangeKindModified},
+			},
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			diffs := Do(yamlA, yamlB, tc.opts...)
+			assert.Len(t, diffs, 1)
+
+			got := []change{}
+			for _, c := range diffs[0].Changes() {
+				got = append(got, change{path: c.Path.String(), kind: c.Kind})
+			}
+
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func Test_performDiff_orderedArrays(t *testing.T) {
+	type child struct {
+		a      rawType
+		b      rawType
+		indexA int
+		indexB int
+		status DiffStatus
+	}
+
+	tests := map[string]struct {
+		a          rawTypeArray
+		b          rawTypeArray
+		opts       doOptions
+		wantStatus DiffStatus
+		want       []child
+	}{
+		"same": {
+			a:          rawTypeArray{1, 2, 3},
+			b:          rawTypeArray{1, 2, 3},
+			wantStatus: DiffStatusSame,
+			want: []child{
+				{a: 1, b: 1, indexA: 0, indexB: 0, status: DiffStatusSame},
+				{a: 2, b: 2, indexA: 1, indexB: 1, status: DiffStatusSame},
+				{a: 3, b: 3, indexA: 2, indexB: 2, status: DiffStatusSame},
+			},
+		},
+		"insert and delete": {
+			a:          rawTypeArray{1, 2, 3},
+			b:          rawTypeArray{1, 3, 4},
+			wantStatus: DiffStatusDiff,
+			want: []child{
+				{a: 1, b: 1, indexA: 0, indexB: 0, status: DiffStatusSame},
+				{a: 2, indexA: 1, indexB: -1, status: DiffStatusDiff},
+				{a: 3, b: 3, indexA: 2, indexB: 1, status: DiffStatusSame},
+				{b: 4, indexA: -1, indexB: 2, status: DiffStatusDiff},
+			},
+		},
+		"modified in place": {
+			a:          rawTypeArray{"a", "b", "c"},
+			b:          rawTypeArray{"a", "x", "c"},
+			wantStatus: DiffStatusDiff,
+			want: []child{
+				{a: "a", b: "a", indexA: 0, indexB: 0, status: DiffStatusSame},
+				{a: "b", b: "x", indexA: 1, indexB: 1, status: DiffStatusDiff},
+				{a: "c", b: "c", indexA: 2, indexB: 2, status: DiffStatusSame},
+			},
+		},
+		"modified below threshold": {
+			a:          rawTypeArray{"a", "foo", "c"},
+			b:          rawTypeArray{"a", "xyzw", "c"},
+			opts:       doOptions{similarityThreshold: 0.5},
+			wantStatus: DiffStatusDiff,
+			want: []child{
+				{a: "a", b: "a", indexA: 0, indexB: 0, status: DiffStatusSame},
+				{a: "foo", indexA: 1, indexB: -1, status: DiffStatusDiff},
+				{b: "xyzw", indexA: -1, indexB: 1, status: DiffStatusDiff},
+				{a: "c", b: "c", indexA: 2, indexB: 2, status: DiffStatusSame},
+			},
+		},
+		"reordered": {
+			a:          rawTypeArray{1, 2, 3},
+			b:          rawTypeArray{3, 1, 2},
+			wantStatus: DiffStatusDiff,
+			want: []child{
+				{a: 3, b: 3, indexA: 2, indexB: 0, status: DiffStatusMoved},
+				{a: 1, b: 1, indexA: 0, indexB: 1, status: DiffStatusSame},
+				{a: 2, b: 2, indexA: 1, indexB: 2, status: DiffStatusSame},
+			},
+		},
+		"with key": {
+			a: rawTypeArray{
+				rawTypeMap{{Key: "name", Value: "a"}, {Key: "v", Value: 1}},
+				rawTypeMap{{Key: "name", Value: "b"}, {Key: "v", Value: 1}},
+			},
+			b: rawTypeArray{
+				rawTypeMap{{Key: "name", Value: "a"}, {Key: "v", Value: 2}},
+				rawTypeMap{{Key: "name", Value: "c"}, {Key: "v", Value: 1}},
+			},
+			opts: doOptions{
+				arrayMatchKeys: []*arrayMatchKey{{path: parsePathPattern(""), key: "name"}},
+			},
+			wantStatus: DiffStatusDiff,
+			want: []child{
+				{
+					a:      rawTypeMap{{Key: "name", Value: "a"}, {Key: "v", Value: 1}},
+					b:      rawTypeMap{{Key: "name", Value: "a"}, {Key: "v", Value: 2}},
+					indexA: 0, indexB: 0, status: DiffStatusDiff,
+				},
+				{a: rawTypeMap{{Key: "name", Value: "b"}, {Key: "v", Value: 1}}, indexA: 1, indexB: -1, status: DiffStatusDiff},
+				{b: rawTypeMap{{Key: "name", Value: "c"}, {Key: "v", Value: 1}}, indexA: -1, indexB: 1, status: DiffStatusDiff},
+			},
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			opts := tc.opts
+			opts.orderedArrays = true
+
+			got := (&runner{option: opts}).performDiff(tc.a, tc.b, 0)
+			assert.Equal(t, tc.wantStatus, got.status)
+
+			children := []child{}
+			for _, c := range got.children.a {
+				children = append(children, child{a: c.a, b: c.b, indexA: c.indexA, indexB: c.indexB, status: c.status})
+			}
+			assert.Equal(t, tc.want, children)
+		})
+	}
+}
+
+func Test_performDiff_similarityThreshold(t *testing.T) {
+	a := rawTypeArray{"foo", "app:1"}
+	b := rawTypeArray{"app:2", "xyzw"}
+
+	got := (&runner{option: doOptions{similarityThreshold: 0.5}}).performDiff(a, b, 0)
+
+	type child struct {
+		a      rawType
+		b      rawType
+		indexA int
+		indexB int
+	}
+
+	children := []child{}
+	for _, c := range got.children.a {
+		children = append(children, child{a: c.a, b: c.b, indexA: c.indexA, indexB: c.indexB})
+	}
+
+	assert.Equal(t, []child{
+		{a: "app:1", b: "app:2", indexA: 1, indexB: 0},
+		{a: "foo", indexA: 0, indexB: -1},
+		{b: "xyzw", indexA: -1, indexB: 1},
+	}, children)
+}
+
+func Test_performDiff_ignorePaths(t *testing.T) {
+	yamlA := mustLoad(t, `
+metadata:
+  name: app
+  uid: aaa
+  annotations:
+    foo: bar
+spec:
+  containers:
+  - name: app
+    image: img:1
+    resourceVersion: "1"
+  replicas: 1
+`)
+	yamlB := mustLoad(t, `
+metadata:
+  name: app
+  uid: bbb
+spec:
+  containers:
+  - name: app
+    image: img:2
+    resourceVersion: "2"
+  replicas: 2
+`)
+
+	tests := map[string]struct {
+		opts []DoOptionFunc
+		want []string
+	}{
+		"default": {
+			want: []string{
+				"metadata.uid",
+				"metadata.annotations",
+				"spec.containers[0].image",
+				"spec.containers[0].resourceVersion",
+				"spec.replicas",
+			},
+		},
+		"ignore": {
+			opts: []DoOptionFunc{IgnorePaths("metadata.uid", "metadata.annotations", "**.resourceVersion", "spec.containers[*].image")},
+			want: []string{"spec.replicas"},
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			diffs := Do(yamlA, yamlB, tc.opts...)
+			assert.Len(t, diffs, 1)
+
+			got := []string{}
+			for _, c := range diffs[0].Changes() {
+				got = append(got, c.Path.String())
+			}
+
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	diffs := Do(yamlA, yamlB, IgnorePaths("metadata", "spec.containers[*]"))
+	assert.Equal(t, 1, diffs[0].d.diffCount)
+	assert.Equal(t, `  spec:
+    containers:
+-   replicas: 1
++   replicas: 2
+`, diffs[0].Dump())
+}
@@ -0,0 +1,219 @@
+package yamldiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/goccy/go-yaml"
+)
+
+type jsonYamlDiff struct {
+	IndexA    *int          `json:"indexA"`
+	IndexB    *int          `json:"indexB"`
+	Status    string        `json:"status"`
+	DiffCount int           `json:"diffCount"`
+	Changes   []*jsonChange `json:"changes"`
+}
+
+type jsonChange struct {
+	Path   string      `json:"path"`
+	Kind   string      `json:"kind"`
+	A      *jsonValue  `json:"a,omitempty"`
+	B      *jsonValue  `json:"b,omitempty"`
+	IndexA *jsonIndex  `json:"indexA,omitempty"`
+	IndexB *jsonIndex  `json:"indexB,omitempty"`
+	Hunks  []*jsonHunk `json:"hunks,omitempty"`
+}
+
+type jsonHunk struct {
+	StartA int      `json:"startA"`
+	LinesA int      `json:"linesA"`
+	StartB int      `json:"startB"`
+	LinesB int      `json:"linesB"`
+	Lines  []string `json:"lines"`
+}
+
+// jsonValue converts yaml values to json, keeping map fields order.
+type jsonValue struct {
+	v interface{}
+}
+
+// EncodeJSON writes diffs as an indented json array.
+func EncodeJSON(w io.Writer, diffs []*YamlDiff) error {
+	if diffs == nil {
+		diffs = []*YamlDiff{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(diffs); err != nil {
+		return fmt.Errorf("yamldiff: failed to encode json: %w", err)
+	}
+
+	return nil
+}
+
+func (y *YamlDiff) MarshalJSON() ([]byte, error) {
+	tree := y.Tree()
+
+	status := tree.Status
+	if status == DiffStatusSame {
+		status = y.Status()
+	}
+
+	out := &jsonYamlDiff{
+		Status:    status.String(),
+		DiffCount: tree.DiffCount,
+		Changes:   []*jsonChange{},
+	}
+	if y.indexA >= 0 {
+		out.IndexA = &y.indexA
+	}
+	if y.indexB >= 0 {
+		out.IndexB = &y.indexB
+	}
+
+	for _, c := range y.Changes() {
+		out.Changes = append(out.Changes, c.toJSON())
+	}
+
+	//nolint:wrapcheck
+	return json.Marshal(out)
+}
+
+// jsonIndex is a position of array element, null if it's missing.
+type jsonIndex struct {
+	i int
+}
+
+func (j *jsonIndex) MarshalJSON() ([]byte, error) {
+	if j.i < 0 {
+		return []byte("null"), nil
+	}
+
+	//nolint:wrapcheck
+	return json.Marshal(j.i)
+}
+
+func (c *Change) MarshalJSON() ([]byte, error) {
+	//nolint:wrapcheck
+	return json.Marshal(c.toJSON())
+}
+
+func (c *Change) toJSON() *jsonChange {
+	out := &jsonChange{
+		Path: c.Path.String(),
+		Kind: c.Kind.String(),
+	}
+	if c.Kind != ChangeKindAdded {
+		out.A = &jsonValue{v: c.A}
+	}
+	if c.Kind != ChangeKindRemoved {
+		out.B = &jsonValue{v: c.B}
+	}
+	if c.IndexA >= 0 || c.IndexB >= 0 {
+		out.IndexA = &jsonIndex{i: c.IndexA}
+		out.IndexB = &jsonIndex{i: c.IndexB}
+	}
+	for _, h := range c.Hunks {
+		out.Hunks = append(out.Hunks, h.toJSON())
+	}
+
+	return out
+}
+
+// toJSON represents lines in unified diff format, e.g. `+added line`.
+func (h *Hunk) toJSON() *jsonHunk {
+	out := &jsonHunk{
+		StartA: h.StartA,
+		LinesA: h.LinesA,
+		StartB: h.StartB,
+		LinesB: h.LinesB,
+		Lines:  make([]string, 0, len(h.Lines)),
+	}
+	for _, l := range h.Lines {
+		out.Lines = append(out.Lines, l.Op.prefix()+l.Text)
+	}
+
+	return out
+}
+
+func (j jsonValue) MarshalJSON() ([]byte, error) {
+	switch t := j.v.(type) {
+	case rawTypeMap:
+		return marshalJSONObject(t)
+	case yaml.MapItem:
+		return marshalJSONObject(rawTypeMap{t})
+	case rawTypeArray:
+		values := make([]jsonValue, 0, len(t))
+		for _, v := range t {
+			values = append(values, jsonValue{v: v})
+		}
+
+		//nolint:wrapcheck
+		return json.Marshal(values)
+	case _missingKey:
+		return []byte("null"), nil
+	case float32:
+		return marshalJSONFloat(float64(t))
+	case float64:
+		return marshalJSONFloat(t)
+	}
+
+	//nolint:wrapcheck
+	return json.Marshal(j.v)
+}
+
+func marshalJSONObject(m rawTypeMap) ([]byte, error) {
+	var b bytes.Buffer
+
+	b.WriteString("{")
+	for i, item := range m {
+		if i > 0 {
+			b.WriteString(",")
+		}
+
+		k, ok := item.Key.(string)
+		if !ok {
+			k = fmt.Sprint(item.Key)
+		}
+
+		key, err := json.Marshal(k)
+		if err != nil {
+			//nolint:wrapcheck
+			return nil, err
+		}
+
+		value, err := json.Marshal(jsonValue{v: item.Value})
+		if err != nil {
+			//nolint:wrapcheck
+			return nil, err
+		}
+
+		b.Write(key)
+		b.WriteString(":")
+		b.Write(value)
+	}
+	b.WriteString("}")
+
+	return b.Bytes(), nil
+}
+
+// json can't represent NaN and Inf, so use yaml notation as string.
+func marshalJSONFloat(f float64) ([]byte, error) {
+	switch {
+	case math.IsNaN(f):
+		return []byte(`".nan"`), nil
+	case math.IsInf(f, 1):
+		return []byte(`".inf"`), nil
+	case math.IsInf(f, -1):
+		return []byte(`"-.inf"`), nil
+	}
+
+	//nolint:wrapcheck
+	return json.Marshal(f)
+}
@@ -0,0 +1,188 @@
+package yamldiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeJSON(t *testing.T) {
+	yamlA, err := Load(`
+kind: Service
+spec:
+  port: 80
+---
+foo: missing-in-b
+`)
+	require.NoError(t, err)
+
+	yamlB, err := Load(`
+kind: Service
+spec:
+  port: 8080
+  selector:
+    app: MyApp
+    tier: web
+`)
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, EncodeJSON(&b, Do(yamlA, yamlB)))
+
+	assert.JSONEq(t, `[
+  {
+    "indexA": 0,
+    "indexB": 0,
+    "status": "diff",
+    "diffCount": 26,
+    "changes": [
+      {"path": "spec.port", "kind": "modified", "a": 80, "b": 8080},
+      {"path": "spec.selector", "kind": "added", "b": {"app": "MyApp", "tier": "web"}}
+    ]
+  },
+  {
+    "indexA": 1,
+    "indexB": null,
+    "status": "missing-in-b",
+    "diffCount": 20,
+    "changes": [
+      {"path": "", "kind": "removed", "a": {"foo": "missing-in-b"}}
+    ]
+  }
+]`, b.String())
+}
+
+func TestEncodeJSON_moved(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, EncodeJSON(&b, Do(mustLoad(t, "[a, b]\n"), mustLoad(t, "[b, a]\n"))))
+
+	assert.JSONEq(t, `[
+  {
+    "indexA": 0,
+    "indexB": 0,
+    "status": "moved",
+    "diffCount": 0,
+    "changes": [
+      {"path": "[0]", "kind": "moved", "a": "a", "b": "a", "indexA": 0, "indexB": 1}
+    ]
+  }
+]`, b.String())
+}
+
+func TestEncodeJSON_arrayElements(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, EncodeJSON(&b, Do(
+		mustLoad(t, "list: [{name: a}, {name: b}, {name: c, v: 1}]\n"),
+		mustLoad(t, "list: [{name: x}, {name: a}, {name: c, v: 2}]\n"),
+		MatchArrayBy("list", "name"),
+	)))
+
+	assert.JSONEq(t, `[
+  {
+    "indexA": 0,
+    "indexB": 0,
+    "status": "diff",
+    "diffCount": 21,
+    "changes": [
+      {"path": "list[1]", "kind": "removed", "a": {"name": "b"}, "indexA": 1, "indexB": null},
+      {"path": "list[2].v", "kind": "modified", "a": 1, "b": 2},
+      {"path": "list[0]", "kind": "added", "b": {"name": "x"}, "indexA": null, "indexB": 0}
+    ]
+  }
+]`, b.String())
+}
+
+func TestEncodeJSON_hunks(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, EncodeJSON(&b, Do(mustLoad(t, "script: |\n  a\n  b\n"), mustLoad(t, "script: |\n  a\n  c\n"))))
+
+	assert.JSONEq(t, `[
+  {
+    "indexA": 0,
+    "indexB": 0,
+    "status": "diff",
+    "diffCount": 2,
+    "changes": [
+      {
+        "path": "script",
+        "kind": "modified",
+        "a": "a\nb\n",
+        "b": "a\nc\n",
+        "hunks": [
+          {"startA": 1, "linesA": 2, "startB": 1, "linesB": 2, "lines": [" a", "-b", "+c"]}
+        ]
+      }
+    ]
+  }
+]`, b.String())
+}
+
+func TestChange_MarshalJSON(t *testing.T) {
+	c := &Change{
+		Path:   Path{}.appendKey("script"),
+		Kind:   ChangeKindModified,
+		A:      "a\n",
+		B:      "",
+		IndexA: -1,
+		IndexB: -1,
+		Hunks:  lineHunks("a\n", "b\nc"),
+	}
+
+	out, err := json.Marshal(c)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "path": "script",
+  "kind": "modified",
+  "a": "a\n",
+  "b": "",
+  "hunks": [
+    {"startA": 1, "linesA": 1, "startB": 1, "linesB": 2, "lines": ["-a", "+b", "+c"]}
+  ]
+}`, string(out))
+}
+
+func TestEncodeJSON_empty(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, EncodeJSON(&b, nil))
+
+	assert.JSONEq(t, `[]`, b.String())
+}
+
+func TestJsonValue_MarshalJSON(t *testing.T) {
+	tests := map[string]struct {
+		v    interface{}
+		want string
+	}{
+		"ordered map": {
+			v:    rawTypeMap{{Key: "z", Value: 1}, {Key: "a", Value: rawTypeArray{"x", nil}}},
+			want: `{"z":1,"a":["x",null]}`,
+		},
+		"non string key": {
+			v:    rawTypeMap{{Key: 1, Value: true}},
+			want: `{"1":true}`,
+		},
+		"nan": {
+			v:    math.NaN(),
+			want: `".nan"`,
+		},
+		"inf": {
+			v:    math.Inf(-1),
+			want: `"-.inf"`,
+		},
+		"missing key": {
+			v:    missingKey,
+			want: `null`,
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			got, err := jsonValue{v: tc.v}.MarshalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, string(got))
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package yamldiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+//nolint:gochecknoglobals
+var (
+	kubernetesAPIVersionPath = parsePathPattern("apiVersion")
+	kubernetesKindPath       = parsePathPattern("kind")
+	kubernetesNamespacePath  = parsePathPattern("metadata.namespace")
+	kubernetesNamePath       = parsePathPattern("metadata.name")
+)
+
+// kubernetesDocumentKey returns the identity of kubernetes object, `group/kind/namespace/name`.
+// The version of apiVersion is ignored to pair the objects across API version upgrades, e.g. `apps/v1beta1` and `apps/v1`.
+func kubernetesDocumentKey(raw rawType) (string, bool) {
+	apiVersion, ok := lookup(raw, kubernetesAPIVersionPath)
+	if !ok {
+		return "", false
+	}
+
+	kind, ok := lookup(raw, kubernetesKindPath)
+	if !ok {
+		return "", false
+	}
+
+	name, ok := lookup(raw, kubernetesNamePath)
+	if !ok {
+		return "", false
+	}
+
+	namespace, ok := lookup(raw, kubernetesNamespacePath)
+	if !ok {
+		namespace = ""
+	}
+
+	group := ""
+	if i := strings.LastIndex(fmt.Sprint(apiVersion), "/"); i >= 0 {
+		group = fmt.Sprint(apiVersion)[:i]
+	}
+
+	return fmt.Sprintf("%s/%s/%s/%s", group, kind, namespace, name), true
+}
@@ -0,0 +1,96 @@
+package yamldiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_kubernetesDocumentKey(t *testing.T) {
+	tests := map[string]struct {
+		yaml   string
+		want   string
+		wantOK bool
+	}{
+		"namespaced": {
+			yaml: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+  namespace: bar`,
+			want:   "apps/Deployment/bar/foo",
+			wantOK: true,
+		},
+		"core group": {
+			yaml: `
+apiVersion: v1
+kind: Service
+metadata:
+  name: foo`,
+			want:   "/Service//foo",
+			wantOK: true,
+		},
+		"not kubernetes object": {
+			yaml: `
+foo: bar`,
+			wantOK: false,
+		},
+	}
+
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			got, ok := kubernetesDocumentKey(mustLoad(t, tt.yaml)[0].raw)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestKubernetesIdentity(t *testing.T) {
+	yamlA := mustLoad(t, `
+apiVersion: apps/v1beta1
+kind: Deployment
+metadata:
+  name: foo
+spec:
+  replicas: 1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+data:
+  foo: bar
+---
+foo: bar`)
+
+	yamlB := mustLoad(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: bar
+data:
+  foo: bar
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+  labels:
+    app: foo
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers: []
+---
+foo: baz`)
+
+	got := [][2]int{}
+	for _, d := range Do(yamlA, yamlB, KubernetesIdentity()) {
+		got = append(got, [2]int{d.indexA, d.indexB})
+	}
+
+	assert.Equal(t, [][2]int{{0, 1}, {1, -1}, {2, 2}, {-1, 0}}, got)
+}
@@ -0,0 +1,68 @@
+package yamldiff
+
+// lcs returns index pairs of the longest common subsequence of two sequences which have n and m elements.
+func lcs(n int, m int, eq func(i int, j int) bool) [][2]int {
+	// the common prefix and suffix are always in a LCS, so the table is built only for the rest
+	prefix := 0
+	for prefix < n && prefix < m && eq(prefix, prefix) {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < n-prefix && suffix < m-prefix && eq(n-1-suffix, m-1-suffix) {
+		suffix++
+	}
+
+	pairs := make([][2]int, 0, prefix+suffix)
+	for k := 0; k < prefix; k++ {
+		pairs = append(pairs, [2]int{k, k})
+	}
+
+	for _, p := range lcsTable(n-prefix-suffix, m-prefix-suffix, func(i int, j int) bool { return eq(i+prefix, j+prefix) }) {
+		pairs = append(pairs, [2]int{p[0] + prefix, p[1] + prefix})
+	}
+
+	for k := suffix; k > 0; k-- {
+		pairs = append(pairs, [2]int{n - k, m - k})
+	}
+
+	return pairs
+}
+
+// lcsTable finds LCS with the dynamic programming table of n x m.
+func lcsTable(n int, m int, eq func(i int, j int) bool) [][2]int {
+	// table[i][j] is the length of LCS of [i:] and [j:]
+	table := make([][]int, n+1)
+	for i := range table {
+		table[i] = make([]int, m+1)
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			switch {
+			case eq(i, j):
+				table[i][j] = table[i+1][j+1] + 1
+			case table[i+1][j] >= table[i][j+1]:
+				table[i][j] = table[i+1][j]
+			default:
+				table[i][j] = table[i][j+1]
+			}
+		}
+	}
+
+	pairs := [][2]int{}
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case table[i][j] == table[i+1][j+1]+1 && eq(i, j):
+			pairs = append(pairs, [2]int{i, j})
+			i++
+			j++
+		case table[i+1][j] >= table[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	return pairs
+}
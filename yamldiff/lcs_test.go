@@ -0,0 +1,62 @@
+package yamldiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_lcs(t *testing.T) {
+	tests := map[string]struct {
+		a    []string
+		b    []string
+		want [][2]int
+	}{
+		"empty": {
+			a:    []string{},
+			b:    []string{"a"},
+			want: [][2]int{},
+		},
+		"same": {
+			a:    []string{"a", "b"},
+			b:    []string{"a", "b"},
+			want: [][2]int{{0, 0}, {1, 1}},
+		},
+		"insert and delete": {
+			a:    []string{"a", "b", "c", "d"},
+			b:    []string{"a", "x", "c", "d", "e"},
+			want: [][2]int{{0, 0}, {2, 2}, {3, 3}},
+		},
+		"common prefix and suffix": {
+			a:    []string{"a", "b", "x", "c", "d"},
+			b:    []string{"a", "b", "y", "z", "c", "d"},
+			want: [][2]int{{0, 0}, {1, 1}, {3, 4}, {4, 5}},
+		},
+		"reordered": {
+			a:    []string{"a", "b", "c"},
+			b:    []string{"c", "a", "b"},
+			want: [][2]int{{0, 1}, {1, 2}},
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			got := lcs(len(tc.a), len(tc.b), func(i int, j int) bool { return tc.a[i] == tc.b[j] })
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func Test_lcs_commonPrefixAndSuffix(t *testing.T) {
+	n := 1000
+	calls := 0
+
+	// only the changed element in the middle needs the table
+	got := lcs(n, n, func(i int, j int) bool {
+		calls++
+
+		return i == j && i != n/2
+	})
+
+	assert.Len(t, got, n-1)
+	assert.Less(t, calls, 3*n)
+}
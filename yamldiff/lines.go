@@ -0,0 +1,160 @@
+package yamldiff
+
+import (
+	"strconv"
+	"strings"
+)
+
+type LineOp int
+
+const (
+	LineOpEqual  LineOp = 1
+	LineOpDelete LineOp = 2
+	LineOpInsert LineOp = 3
+
+	hunkContext = 3
+)
+
+func (o LineOp) prefix() string {
+	switch o {
+	case LineOpDelete:
+		return "-"
+	case LineOpInsert:
+		return "+"
+	}
+
+	return " "
+}
+
+// Line is a line of multi-line string in a Hunk.
+type Line struct {
+	Op   LineOp
+	Text string
+}
+
+// Hunk is a part of line-level diff of multi-line strings, like a unified diff.
+// StartA and StartB are 1-based line numbers, LinesA and LinesB are the number of lines in each side.
+type Hunk struct {
+	StartA int
+	LinesA int
+	StartB int
+	LinesB int
+	Lines  []*Line
+}
+
+// Header returns the unified diff header, e.g. `@@ -1,3 +1,4 @@`.
+func (h *Hunk) Header() string {
+	return "@@ -" + hunkRange(h.StartA, h.LinesA) + " +" + hunkRange(h.StartB, h.LinesB) + " @@"
+}
+
+func hunkRange(start int, lines int) string {
+	if lines == 0 {
+		// unified diff points the line before the empty range
+		start--
+	}
+
+	return strconv.Itoa(start) + "," + strconv.Itoa(lines)
+}
+
+// isMultiline checks the values are strings and either of them has multiple lines.
+func isMultiline(rawA rawType, rawB rawType) bool {
+	strA, okA := rawA.(string)
+	strB, okB := rawB.(string)
+
+	return okA && okB && (strings.Contains(strA, "\n") || strings.Contains(strB, "\n"))
+}
+
+// splitLines splits the string into lines, the empty string has no lines.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines returns line-level diff of the strings aligned by LCS.
+func diffLines(a string, b string) []*Line {
+	linesA := splitLines(a)
+	linesB := splitLines(b)
+
+	pairs := lcs(len(linesA), len(linesB), func(i int, j int) bool {
+		return linesA[i] == linesB[j]
+	})
+
+	result := []*Line{}
+	i, j := 0, 0
+	for _, p := range append(pairs, [2]int{len(linesA), len(linesB)}) {
+		for ; i < p[0]; i++ {
+			result = append(result, &Line{Op: LineOpDelete, Text: linesA[i]})
+		}
+		for ; j < p[1]; j++ {
+			result = append(result, &Line{Op: LineOpInsert, Text: linesB[j]})
+		}
+
+		if p[0] < len(linesA) {
+			result = append(result, &Line{Op: LineOpEqual, Text: linesA[i]})
+			i++
+			j++
+		}
+	}
+
+	return result
+}
+
+// lineHunks returns line-level diff of multi-line strings, nil if the values are not.
+func lineHunks(rawA rawType, rawB rawType) []*Hunk {
+	if !isMultiline(rawA, rawB) {
+		return nil
+	}
+
+	strA, _ := rawA.(string)
+	strB, _ := rawB.(string)
+
+	return hunks(diffLines(strA, strB), hunkContext)
+}
+
+// hunks groups the changed lines with the surrounding context lines.
+func hunks(lines []*Line, context int) []*Hunk {
+	// ranges of lines to include, [start, end)
+	ranges := [][2]int{}
+	for n, l := range lines {
+		if l.Op == LineOpEqual {
+			continue
+		}
+
+		start, end := max(n-context, 0), min(n+context+1, len(lines))
+		if last := len(ranges) - 1; last >= 0 && start <= ranges[last][1] {
+			ranges[last][1] = end
+
+			continue
+		}
+
+		ranges = append(ranges, [2]int{start, end})
+	}
+
+	result := make([]*Hunk, 0, len(ranges))
+	posA, posB, n := 1, 1, 0
+	for _, r := range ranges {
+		// lines out of hunks are always the same
+		posA += r[0] - n
+		posB += r[0] - n
+		n = r[0]
+
+		h := &Hunk{StartA: posA, StartB: posB, Lines: lines[r[0]:r[1]]}
+		for ; n < r[1]; n++ {
+			if lines[n].Op != LineOpInsert {
+				h.LinesA++
+				posA++
+			}
+			if lines[n].Op != LineOpDelete {
+				h.LinesB++
+				posB++
+			}
+		}
+
+		result = append(result, h)
+	}
+
+	return result
+}
@@ -0,0 +1,76 @@
+package yamldiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_lineHunks(t *testing.T) {
+	tests := map[string]struct {
+		a    rawType
+		b    rawType
+		want []string
+	}{
+		"not multi-line": {
+			a:    "foo",
+			b:    "bar",
+			want: []string{},
+		},
+		"not string": {
+			a:    "foo\nbar",
+			b:    uint64(1),
+			want: []string{},
+		},
+		"changed line": {
+			a:    "a\nb\nc\n",
+			b:    "a\nB\nc\n",
+			want: []string{"@@ -1,3 +1,3 @@", " a", "-b", "+B", " c"},
+		},
+		"added lines": {
+			a:    "a",
+			b:    "a\nb\nc",
+			want: []string{"@@ -1,1 +1,3 @@", " a", "+b", "+c"},
+		},
+		"removed all": {
+			a:    "a\nb",
+			b:    "",
+			want: []string{"@@ -1,2 +0,0 @@", "-a", "-b"},
+		},
+		"separated hunks": {
+			a: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10",
+			b: "0\n2\n3\n4\n5\n6\n7\n8\n9\n11",
+			want: []string{
+				"@@ -1,4 +1,4 @@", "-1", "+0", " 2", " 3", " 4",
+				"@@ -7,4 +7,4 @@", " 7", " 8", " 9", "-10", "+11",
+			},
+		},
+		"joined hunks": {
+			a:    "1\n2\n3\n4\n5\n6\n7\n8",
+			b:    "0\n2\n3\n4\n5\n6\n7\n9",
+			want: []string{"@@ -1,8 +1,8 @@", "-1", "+0", " 2", " 3", " 4", " 5", " 6", " 7", "-8", "+9"},
+		},
+		"empty range": {
+			a:    "a\nb\nc\nd\ne",
+			b:    "a\nb\nc\nd\ne\nf",
+			want: []string{"@@ -3,3 +3,4 @@", " c", " d", " e", "+f"},
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			got := []string{}
+			for _, h := range lineHunks(tc.a, tc.b) {
+				got = append(got, h.Header())
+				for _, l := range h.Lines {
+					got = append(got, l.Op.prefix()+l.Text)
+				}
+			}
+
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestHunk_Header(t *testing.T) {
+	assert.Equal(t, "@@ -1,2 +0,0 @@", (&Hunk{StartA: 1, LinesA: 2, StartB: 1, LinesB: 0}).Header())
+}
@@ -0,0 +1,299 @@
+package yamldiff
+
+import (
+	"github.com/goccy/go-yaml"
+)
+
+// Conflict is a path changed differently in ours and theirs.
+// Document is the position of the document in base.
+type Conflict struct {
+	Document int
+	Path     Path
+	Base     interface{}
+	Ours     interface{}
+	Theirs   interface{}
+}
+
+// Merge performs three-way merge of the documents.
+// Changes of base→ours and base→theirs are merged by path, ours is taken for a conflicted path.
+// Array elements are paired with the base elements in the same way as Do, and elements added in ours or theirs are kept.
+// Documents added in ours or theirs are appended to the result.
+func Merge(base RawYamlList, ours RawYamlList, theirs RawYamlList, options ...DoOptionFunc) (RawYamlList, []*Conflict) {
+	opts := &doOptions{}
+	for _, o := range options {
+		o(opts)
+	}
+
+	m := &merger{
+		r:         &runner{option: *opts},
+		conflicts: []*Conflict{},
+	}
+
+	oursDiffs := Do(base, ours, options...)
+	theirsDiffs := Do(base, theirs, options...)
+
+	results := RawYamlList{}
+	for i, b := range base {
+		m.document = i
+
+		merged := m.merge(b.raw, pairedRaw(oursDiffs, ours, i), pairedRaw(theirsDiffs, theirs, i), Path{})
+		if merged != missingKey {
+			results = append(results, newRawYaml(merged))
+		}
+	}
+
+	oursAdded := addedRaws(oursDiffs, ours)
+	results = append(results, oursAdded...)
+
+	for _, t := range addedRaws(theirsDiffs, theirs) {
+		if !m.containsSame(oursAdded, t) {
+			results = append(results, t)
+		}
+	}
+
+	return results, m.conflicts
+}
+
+type merger struct {
+	r         *runner
+	document  int
+	conflicts []*Conflict
+}
+
+func (m *merger) merge(base rawType, ours rawType, theirs rawType, path Path) rawType {
+	switch {
+	case m.same(ours, theirs, path):
+		return ours
+	case m.same(base, ours, path):
+		return theirs
+	case m.same(base, theirs, path):
+		return ours
+	}
+
+	mapB, mapBok := tryMap(base)
+	mapO, mapOok := tryMap(ours)
+	mapT, mapTok := tryMap(theirs)
+
+	if mapOok && mapTok && (mapBok || base == missingKey) {
+		return m.mergeMap(mapB, mapO, mapT, path)
+	}
+
+	arrayB, arrayBok := tryArray(base)
+	arrayO, arrayOok := tryArray(ours)
+	arrayT, arrayTok := tryArray(theirs)
+
+	if arrayOok && arrayTok && (arrayBok || base == missingKey) {
+		return m.mergeArray(arrayB, arrayO, arrayT, path)
+	}
+
+	m.conflicts = append(m.conflicts, &Conflict{
+		Document: m.document,
+		Path:     path,
+		Base:     nodeValue(base),
+		Ours:     nodeValue(ours),
+		Theirs:   nodeValue(theirs),
+	})
+
+	return ours
+}
+
+func (m *merger) mergeMap(base rawTypeMap, ours rawTypeMap, theirs rawTypeMap, path Path) rawTypeMap {
+	keys := []yaml.MapItem{}
+	checked := map[string]struct{}{}
+
+	for _, r := range []rawTypeMap{base, ours, theirs} {
+		for _, item := range r {
+			k := mapKey(item)
+			if _, ok := checked[k]; ok {
+				continue
+			}
+
+			keys = append(keys, item)
+			checked[k] = struct{}{}
+		}
+	}
+
+	result := rawTypeMap{}
+	for _, item := range keys {
+		k := mapKey(item)
+
+		v := m.merge(mapValue(base, k), mapValue(ours, k), mapValue(theirs, k), path.appendKey(k))
+		if v != missingKey {
+			result = append(result, yaml.MapItem{Key: item.Key, Value: v})
+		}
+	}
+
+	return result
+}
+
+// mergeArray merges the elements of ours with the paired elements of base and theirs.
+// Elements added in theirs are placed after the element which precedes them in theirs.
+// Paths of conflicts in the array have the positions in base.
+func (m *merger) mergeArray(base rawTypeArray, ours rawTypeArray, theirs rawTypeArray, path Path) rawTypeArray {
+	baseO := m.basePositions(base, ours, path)
+	baseT := m.basePositions(base, theirs, path)
+
+	inOurs := map[int]struct{}{}
+	oursAdded := rawTypeArray{}
+	for j, i := range baseO {
+		if i < 0 {
+			oursAdded = append(oursAdded, ours[j])
+
+			continue
+		}
+
+		inOurs[i] = struct{}{}
+	}
+
+	inTheirs := map[int]rawType{}
+	theirsAdded := map[int]rawTypeArray{}
+	anchor := -1
+	for k, i := range baseT {
+		if i < 0 {
+			theirsAdded[anchor] = append(theirsAdded[anchor], theirs[k])
+
+			continue
+		}
+
+		inTheirs[i] = theirs[k]
+		if _, ok := inOurs[i]; ok {
+			anchor = i
+		}
+	}
+
+	theirsValue := func(i int) rawType {
+		if v, ok := inTheirs[i]; ok {
+			return v
+		}
+
+		return missingKey
+	}
+
+	// elements removed in ours are never in the result, but changes in theirs conflict with the removal
+	for i := range base {
+		if _, ok := inOurs[i]; !ok {
+			m.merge(base[i], missingKey, theirsValue(i), path.appendIndex(i))
+		}
+	}
+
+	result := rawTypeArray{}
+	appendTheirs := func(anchor int) {
+		for _, v := range theirsAdded[anchor] {
+			if !m.containsSameValue(oursAdded, v, path) {
+				result = append(result, v)
+			}
+		}
+	}
+
+	appendTheirs(-1)
+	for j, i := range baseO {
+		if i < 0 {
+			result = append(result, ours[j])
+
+			continue
+		}
+
+		if v := m.merge(base[i], ours[j], theirsValue(i), path.appendIndex(i)); v != missingKey {
+			result = append(result, v)
+		}
+		appendTheirs(i)
+	}
+
+	return result
+}
+
+// basePositions returns the position of the paired base element for each element, -1 if it's added.
+func (m *merger) basePositions(base rawTypeArray, array rawTypeArray, path Path) []int {
+	positions := make([]int, len(array))
+	for i := range positions {
+		positions[i] = -1
+	}
+
+	if base == nil {
+		base = rawTypeArray{}
+	}
+
+	for _, c := range m.diff(base, array, path).children.a {
+		if c.indexA >= 0 && c.indexB >= 0 {
+			positions[c.indexB] = c.indexA
+		}
+	}
+
+	return positions
+}
+
+// diff compares the values at the path, so that options for paths are applied in the same way as Do.
+func (m *merger) diff(a rawType, b rawType, path Path) *diff {
+	m.r.path = path
+
+	return m.r.performDiff(a, b, 0)
+}
+
+// same reports whether the values have no change, so that reordered arrays are changes to keep.
+func (m *merger) same(a rawType, b rawType, path Path) bool {
+	if a == missingKey && b == missingKey {
+		return true
+	}
+
+	return !m.diff(a, b, path).changed()
+}
+
+func (m *merger) containsSame(raws RawYamlList, raw *RawYaml) bool {
+	for _, r := range raws {
+		if m.same(r.raw, raw.raw, Path{}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *merger) containsSameValue(values rawTypeArray, v rawType, path Path) bool {
+	for _, value := range values {
+		if m.same(value, v, path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func mapKey(item yaml.MapItem) string {
+	k, ok := item.Key.(string)
+	if !ok {
+		k = fakeForMissingKey
+	}
+
+	return k
+}
+
+func mapValue(m rawTypeMap, k string) rawType {
+	for _, item := range m {
+		if mapKey(item) == k {
+			return item.Value
+		}
+	}
+
+	return missingKey
+}
+
+func pairedRaw(diffs []*YamlDiff, raws RawYamlList, indexA int) rawType {
+	for _, d := range diffs {
+		if d.indexA == indexA && d.indexB >= 0 {
+			return raws[d.indexB].raw
+		}
+	}
+
+	return missingKey
+}
+
+func addedRaws(diffs []*YamlDiff, raws RawYamlList) RawYamlList {
+	results := RawYamlList{}
+	for _, d := range diffs {
+		if d.indexA < 0 {
+			results = append(results, raws[d.indexB])
+		}
+	}
+
+	return results
+}
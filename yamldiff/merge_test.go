@@ -0,0 +1,179 @@
+package yamldiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	base := mustLoad(t, `
+name: app
+image: app:1.0.0
+replicas: 1
+env:
+  FOO: foo
+  BAR: bar
+---
+removed: in-ours
+`)
+	ours := mustLoad(t, `
+name: app
+image: app:1.1.0
+replicas: 1
+env:
+  FOO: foo
+  BAR: bar
+  OURS: added
+`)
+	theirs := mustLoad(t, `
+name: app
+image: app:1.0.0
+replicas: 3
+env:
+  FOO: changed
+  THEIRS: added
+---
+removed: in-ours
+---
+added: in-theirs
+`)
+
+	merged, conflicts := Merge(base, ours, theirs)
+	assert.Empty(t, conflicts)
+
+	out, err := Marshal(merged)
+	require.NoError(t, err)
+	assert.Equal(t, `name: app
+image: app:1.1.0
+replicas: 3
+env:
+  FOO: changed
+  OURS: added
+  THEIRS: added
+---
+added: in-theirs
+`, out)
+}
+
+func TestMerge_conflicts(t *testing.T) {
+	base := mustLoad(t, "image: app:1.0.0\nlist: [1, 10]\nsame: 1\n---\nname: doc\n")
+	ours := mustLoad(t, "image: app:1.1.0\nlist: [1, 20]\nsame: 2\n")
+	theirs := mustLoad(t, "image: app:1.2.0\nlist: [1, 30]\nsame: 2\n---\nname: doc\nchanged: true\n")
+
+	merged, conflicts := Merge(base, ours, theirs)
+
+	out, err := Marshal(merged)
+	require.NoError(t, err)
+	assert.Equal(t, "image: app:1.1.0\nlist:\n- 1\n- 20\nsame: 2\n", out)
+
+	got := []string{}
+	for _, c := range conflicts {
+		got = append(got, c.Path.String())
+	}
+	assert.Equal(t, []string{"image", "list[1]", ""}, got)
+
+	assert.Equal(t, &Conflict{
+		Document: 0,
+		Path:     Path{}.appendKey("image"),
+		Base:     "app:1.0.0",
+		Ours:     "app:1.1.0",
+		Theirs:   "app:1.2.0",
+	}, conflicts[0])
+	assert.Equal(t, 1, conflicts[2].Document)
+	assert.Nil(t, conflicts[2].Ours)
+}
+
+func TestMerge_sameAddition(t *testing.T) {
+	base := mustLoad(t, "foo: bar\n")
+	added := mustLoad(t, "foo: bar\n---\nnew: doc\n")
+
+	merged, conflicts := Merge(base, added, added)
+	assert.Empty(t, conflicts)
+	assert.Len(t, merged, 2)
+}
+
+func TestMerge_arrays(t *testing.T) {
+	tests := map[string]struct {
+		base      string
+		ours      string
+		theirs    string
+		options   []DoOptionFunc
+		want      string
+		conflicts []string
+	}{
+		"concurrent additions": {
+			base:   "list: [1, 2]\n",
+			ours:   "list: [1, 2, 3]\n",
+			theirs: "list: [0, 1, 2]\n",
+			want:   "list:\n- 0\n- 1\n- 2\n- 3\n",
+		},
+		"same addition": {
+			base:   "list: [1]\n",
+			ours:   "list: [1, 2]\n",
+			theirs: "list: [1, 2]\n",
+			want:   "list:\n- 1\n- 2\n",
+		},
+		"removal and addition": {
+			base:   "list: [1, 2, 3]\n",
+			ours:   "list: [1, 3]\n",
+			theirs: "list: [1, 2, 3, 4]\n",
+			want:   "list:\n- 1\n- 3\n- 4\n",
+		},
+		"elements by key": {
+			base:    "list:\n- {name: a, v: 1}\n- {name: b, v: 1}\n",
+			ours:    "list:\n- {name: b, v: 2}\n- {name: a, v: 1}\n",
+			theirs:  "list:\n- {name: a, v: 3}\n- {name: b, v: 1}\n- {name: c, v: 1}\n",
+			options: []DoOptionFunc{MatchArrayBy("list", "name")},
+			want:    "list:\n- name: b\n  v: 2\n- name: c\n  v: 1\n- name: a\n  v: 3\n",
+		},
+		"reordered in ours": {
+			base:   "image: v1\nargs: [--a, --b]\n",
+			ours:   "image: v1\nargs: [--b, --a]\n",
+			theirs: "image: v2\nargs: [--a, --b]\n",
+			want:   "image: v2\nargs:\n- --b\n- --a\n",
+		},
+		"changed and removed": {
+			base:      "name: app\nlist: [{name: a, v: 1}]\n",
+			ours:      "name: app\nlist: []\n",
+			theirs:    "name: app\nlist: [{name: a, v: 2}]\n",
+			want:      "name: app\nlist: []\n",
+			conflicts: []string{"list[0]"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			merged, conflicts := Merge(mustLoad(t, tt.base), mustLoad(t, tt.ours), mustLoad(t, tt.theirs), tt.options...)
+
+			got := []string{}
+			for _, c := range conflicts {
+				got = append(got, c.Path.String())
+			}
+			if tt.conflicts == nil {
+				tt.conflicts = []string{}
+			}
+			assert.Equal(t, tt.conflicts, got)
+
+			out, err := Marshal(merged)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out)
+		})
+	}
+}
+
+func TestMerge_ignorePaths(t *testing.T) {
+	base := mustLoad(t, "spec:\n  metadata:\n    annotations: 1\n  replicas: 1\n")
+	ours := mustLoad(t, "spec:\n  metadata:\n    annotations: 2\n  replicas: 1\n")
+	theirs := mustLoad(t, "spec:\n  metadata:\n    annotations: 3\n  replicas: 2\n")
+
+	// the pattern matches from the root, so that it doesn't hide the nested annotations
+	merged, conflicts := Merge(base, ours, theirs, IgnorePaths("metadata.annotations"))
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, "spec.metadata.annotations", conflicts[0].Path.String())
+
+	out, err := Marshal(merged)
+	require.NoError(t, err)
+	assert.Equal(t, "spec:\n  metadata:\n    annotations: 2\n  replicas: 2\n", out)
+}
@@ -0,0 +1,78 @@
+package yamldiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/goccy/go-yaml"
+)
+
+// MergePatch returns RFC 7386 JSON Merge Patch which transforms A into B.
+// It contains only the changed fields, removed fields are nil and arrays are replaced as a whole, also when the elements are only reordered.
+// Note that merge patch can't express a field changed to null, it's treated as removed.
+func (y *YamlDiff) MergePatch() interface{} {
+	n := y.Tree()
+	if n.Status == DiffStatusSame && !isReordered(n) {
+		return rawTypeMap{}
+	}
+
+	return mergePatch(n)
+}
+
+func mergePatch(n *Node) interface{} {
+	if n.Kind != NodeKindMap || (n.Status != DiffStatusDiff && !isReordered(n)) || len(n.Children) == 0 {
+		return n.B
+	}
+
+	patch := rawTypeMap{}
+	for _, c := range n.Children {
+		key := c.Path[len(c.Path)-1].Key
+
+		switch {
+		case c.Status == DiffStatusSame && !isReordered(c):
+			continue
+		case c.Status == DiffStatus2Missing:
+			patch = append(patch, yaml.MapItem{Key: key, Value: nil})
+		default:
+			patch = append(patch, yaml.MapItem{Key: key, Value: mergePatch(c)})
+		}
+	}
+
+	return patch
+}
+
+// EncodeMergePatchYAML writes merge patch of each diff as `---` separated yaml documents.
+func EncodeMergePatchYAML(w io.Writer, diffs []*YamlDiff) error {
+	for i, d := range diffs {
+		b, err := yaml.Marshal(d.MergePatch())
+		if err != nil {
+			return fmt.Errorf("yamldiff: failed to encode merge patch: %w", err)
+		}
+
+		if i > 0 {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return fmt.Errorf("yamldiff: failed to write merge patch: %w", err)
+			}
+		}
+
+		if _, err := w.Write(b); err != nil {
+			return fmt.Errorf("yamldiff: failed to write merge patch: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// EncodeMergePatchJSON writes merge patch of each diff as a json line.
+func EncodeMergePatchJSON(w io.Writer, diffs []*YamlDiff) error {
+	enc := json.NewEncoder(w)
+
+	for _, d := range diffs {
+		if err := enc.Encode(jsonValue{v: d.MergePatch()}); err != nil {
+			return fmt.Errorf("yamldiff: failed to encode merge patch: %w", err)
+		}
+	}
+
+	return nil
+}
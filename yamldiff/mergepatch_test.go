@@ -0,0 +1,84 @@
+package yamldiff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYamlDiff_MergePatch(t *testing.T) {
+	tests := map[string]struct {
+		a    string
+		b    string
+		want interface{}
+	}{
+		"same": {
+			a:    "foo: bar\n",
+			b:    "foo: bar\n",
+			want: rawTypeMap{},
+		},
+		"map": {
+			a: "foo: bar\nsame: 1\nremoved: 1\nnested:\n  a: 1\n  b: 1\n",
+			b: "foo: baz\nsame: 1\nnested:\n  a: 2\n  b: 1\nadded: true\n",
+			want: rawTypeMap{
+				{Key: "foo", Value: "baz"},
+				{Key: "removed", Value: nil},
+				{Key: "nested", Value: rawTypeMap{{Key: "a", Value: uint64(2)}}},
+				{Key: "added", Value: true},
+			},
+		},
+		"array is replaced": {
+			a: "list: [1, 2]\n",
+			b: "list: [1, 3]\n",
+			want: rawTypeMap{
+				{Key: "list", Value: rawTypeArray{uint64(1), uint64(3)}},
+			},
+		},
+		"array only reordered": {
+			a: "same: 1\nnested:\n  list: [a, b, c]\n",
+			b: "same: 1\nnested:\n  list: [b, c, a]\n",
+			want: rawTypeMap{
+				{Key: "nested", Value: rawTypeMap{{Key: "list", Value: rawTypeArray{"b", "c", "a"}}}},
+			},
+		},
+		"not map": {
+			a:    "foo\n",
+			b:    "- bar\n",
+			want: rawTypeArray{"bar"},
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			yamlA, err := Load(tc.a)
+			require.NoError(t, err)
+
+			yamlB, err := Load(tc.b)
+			require.NoError(t, err)
+
+			diffs := Do(yamlA, yamlB)
+			require.Len(t, diffs, 1)
+
+			assert.Equal(t, tc.want, diffs[0].MergePatch())
+		})
+	}
+}
+
+func TestEncodeMergePatch(t *testing.T) {
+	yamlA, err := Load("foo: bar\nbaz: 1\n---\nremoved: true\n")
+	require.NoError(t, err)
+
+	yamlB, err := Load("foo: qux\n")
+	require.NoError(t, err)
+
+	diffs := Do(yamlA, yamlB)
+
+	var y bytes.Buffer
+	require.NoError(t, EncodeMergePatchYAML(&y, diffs))
+	assert.Equal(t, "foo: qux\nbaz: null\n---\nnull\n", y.String())
+
+	var j bytes.Buffer
+	require.NoError(t, EncodeMergePatchJSON(&j, diffs))
+	assert.Equal(t, "{\"foo\":\"qux\",\"baz\":null}\nnull\n", j.String())
+}
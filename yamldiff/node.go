@@ -0,0 +1,139 @@
+package yamldiff
+
+type NodeKind int
+
+const (
+	NodeKindScalar NodeKind = 1
+	NodeKindMap    NodeKind = 2
+	NodeKindArray  NodeKind = 3
+)
+
+// Node is a public, read-only view of the diff tree.
+// A and B hold the values of each side, nil if the value is missing.
+// Kind is the kind of B, or A if B is missing.
+// TypeChanged tells the scalars are the same only after type coercion, see ReportCoercedTypes.
+// IndexA and IndexB are positions of array element, -1 if it's missing or not an array element.
+// Path addresses array elements by the position in A, or by the position in B for elements only in B.
+type Node struct {
+	Path      Path
+	Kind      NodeKind
+	Status    DiffStatus
+	A         interface{}
+	B         interface{}
+	IndexA    int
+	IndexB    int
+	DiffCount int
+	Children  []*Node
+
+	TypeChanged bool
+}
+
+// Walk visits the node and its descendants in depth-first order.
+// Children of a node are skipped when fn returns false.
+func (n *Node) Walk(fn func(n *Node) bool) {
+	if !fn(n) {
+		return
+	}
+
+	for _, c := range n.Children {
+		c.Walk(fn)
+	}
+}
+
+func (y *YamlDiff) Tree() *Node {
+	n := y.d.node(Path{})
+	n.Status = missingStatus(n.Status, y.indexA, y.indexB)
+
+	return n
+}
+
+func (y *YamlDiff) Walk(fn func(n *Node) bool) {
+	y.Tree().Walk(fn)
+}
+
+func (d *diff) node(path Path) *Node {
+	n := &Node{
+		Path:      path,
+		Kind:      kindOf(d.b),
+		Status:    d.status,
+		A:         nodeValue(d.a),
+		B:         nodeValue(d.b),
+		IndexA:    -1,
+		IndexB:    -1,
+		DiffCount: d.diffCount,
+
+		TypeChanged: d.typeChanged,
+	}
+	if n.B == nil {
+		n.Kind = kindOf(d.a)
+	}
+
+	if d.children == nil {
+		return n
+	}
+
+	if d.children.a != nil {
+		n.Kind = NodeKindArray
+		for _, c := range d.children.a {
+			if c.ignored {
+				continue
+			}
+
+			index := c.indexA
+			if index < 0 {
+				index = c.indexB
+			}
+
+			child := c.node(path.appendIndex(index))
+			child.Status = missingStatus(child.Status, c.indexA, c.indexB)
+			child.IndexA = c.indexA
+			child.IndexB = c.indexB
+			n.Children = append(n.Children, child)
+		}
+	}
+
+	if d.children.m != nil {
+		n.Kind = NodeKindMap
+		for _, c := range d.sortedMapChildren() {
+			n.Children = append(n.Children, c.v.node(path.appendKey(c.k)))
+		}
+	}
+
+	return n
+}
+
+// missingStatus tells that array element or document exists only one side.
+func missingStatus(status DiffStatus, indexA int, indexB int) DiffStatus {
+	if status == DiffStatusSame {
+		return status
+	}
+
+	switch {
+	case indexA < 0:
+		return DiffStatus1Missing
+	case indexB < 0:
+		return DiffStatus2Missing
+	}
+
+	return status
+}
+
+func nodeValue(v rawType) interface{} {
+	if v == missingKey {
+		return nil
+	}
+
+	return v
+}
+
+func kindOf(v rawType) NodeKind {
+	if _, ok := tryMap(v); ok {
+		return NodeKindMap
+	}
+
+	if _, ok := tryArray(v); ok {
+		return NodeKindArray
+	}
+
+	return NodeKindScalar
+}
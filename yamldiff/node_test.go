@@ -0,0 +1,102 @@
+package yamldiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYamlDiff_Walk(t *testing.T) {
+	yamlA, err := Load(`
+name: app
+spec:
+  replicas: 3
+  ports:
+  - 80
+  - 443
+removed: true
+`)
+	require.NoError(t, err)
+
+	yamlB, err := Load(`
+name: app
+spec:
+  replicas: 10
+  ports:
+  - 80
+  - 443
+  - 8080
+added: true
+`)
+	require.NoError(t, err)
+
+	diffs := Do(yamlA, yamlB)
+	require.Len(t, diffs, 1)
+
+	type visited struct {
+		path   string
+		kind   NodeKind
+		status DiffStatus
+		a      interface{}
+		b      interface{}
+	}
+
+	got := []visited{}
+	diffs[0].Walk(func(n *Node) bool {
+		v := visited{path: n.Path.String(), kind: n.Kind, status: n.Status}
+		if n.Kind == NodeKindScalar {
+			v.a = n.A
+			v.b = n.B
+		}
+		got = append(got, v)
+
+		return true
+	})
+
+	assert.Equal(t, []visited{
+		{path: "", kind: NodeKindMap, status: DiffStatusDiff},
+		{path: "name", kind: NodeKindScalar, status: DiffStatusSame, a: "app", b: "app"},
+		{path: "spec", kind: NodeKindMap, status: DiffStatusDiff},
+		{path: "spec.replicas", kind: NodeKindScalar, status: DiffStatusDiff, a: uint64(3), b: uint64(10)},
+		{path: "spec.ports", kind: NodeKindArray, status: DiffStatusDiff},
+		{path: "spec.ports[0]", kind: NodeKindScalar, status: DiffStatusSame, a: uint64(80), b: uint64(80)},
+		{path: "spec.ports[1]", kind: NodeKindScalar, status: DiffStatusSame, a: uint64(443), b: uint64(443)},
+		{path: "spec.ports[2]", kind: NodeKindScalar, status: DiffStatus1Missing, b: uint64(8080)},
+		{path: "removed", kind: NodeKindScalar, status: DiffStatus2Missing, a: true},
+		{path: "added", kind: NodeKindScalar, status: DiffStatus1Missing, b: true},
+	}, got)
+}
+
+func TestYamlDiff_Walk_skipChildren(t *testing.T) {
+	yamlA, err := Load("spec:\n  replicas: 3\n")
+	require.NoError(t, err)
+
+	yamlB, err := Load("spec:\n  replicas: 10\n")
+	require.NoError(t, err)
+
+	got := []string{}
+	Do(yamlA, yamlB)[0].Walk(func(n *Node) bool {
+		got = append(got, n.Path.String())
+
+		return n.Path.String() != "spec"
+	})
+
+	assert.Equal(t, []string{"", "spec"}, got)
+}
+
+func TestYamlDiff_Tree_missingDocument(t *testing.T) {
+	yamlA, err := Load("foo: bar\n")
+	require.NoError(t, err)
+
+	yamlB, err := Load("baz: 1\n---\nfoo: bar\n")
+	require.NoError(t, err)
+
+	diffs := Do(yamlA, yamlB)
+	require.Len(t, diffs, 2)
+
+	assert.Equal(t, DiffStatusSame, diffs[0].Tree().Status)
+	assert.Equal(t, DiffStatus1Missing, diffs[1].Tree().Status)
+	assert.Equal(t, NodeKindMap, diffs[1].Tree().Kind)
+	assert.Nil(t, diffs[1].Tree().A)
+}
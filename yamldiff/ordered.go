@@ -0,0 +1,166 @@
+package yamldiff
+
+import "slices"
+
+// handleOrderedArray aligns elements with LCS and keeps the source order in children.
+// Elements between aligned ones are paired by position, and the same elements at different positions are
+// reported as moved at the new position.
+func (r *runner) handleOrderedArray(result *diff, arrayA rawTypeArray, arrayB rawTypeArray, level int) *diff {
+	key, hasKey := r.arrayMatchKey()
+
+	diffs := map[[2]int]*diff{}
+	diffAt := func(i int, j int) *diff {
+		if d, ok := diffs[[2]int{i, j}]; ok {
+			return d
+		}
+
+		d := r.performArrayElementDiff(arrayA[i], arrayB[j], i, j, level)
+		diffs[[2]int{i, j}] = d
+
+		return d
+	}
+
+	eq := func(i int, j int) bool {
+		if hasKey {
+			idA, okA := elementID(arrayA[i], key)
+			idB, okB := elementID(arrayB[j], key)
+			if okA || okB {
+				return okA && okB && idA == idB
+			}
+		}
+
+		return diffAt(i, j).status == DiffStatusSame
+	}
+
+	pairs := lcs(len(arrayA), len(arrayB), eq)
+
+	alignedA := map[int]struct{}{}
+	alignedB := map[int]struct{}{}
+	for _, p := range pairs {
+		alignedA[p[0]] = struct{}{}
+		alignedB[p[1]] = struct{}{}
+	}
+
+	movedA, movedB := findMoved(len(arrayA), len(arrayB), alignedA, alignedB, func(i int, j int) bool {
+		return diffAt(i, j).status == DiffStatusSame
+	})
+
+	gap := &orderedGap{
+		r:       r,
+		arrayA:  arrayA,
+		arrayB:  arrayB,
+		movedA:  movedA,
+		movedB:  movedB,
+		pair:    !hasKey,
+		diffAt:  diffAt,
+		similar: r.similarPairs(arrayA, arrayB, true),
+		level:   level,
+	}
+
+	i, j := 0, 0
+	for _, p := range append(pairs, [2]int{len(arrayA), len(arrayB)}) {
+		result.children.a = append(result.children.a, gap.children(i, p[0], j, p[1])...)
+		if p[0] < len(arrayA) {
+			result.children.a = append(result.children.a, diffAt(p[0], p[1]))
+		}
+
+		i, j = p[0]+1, p[1]+1
+	}
+
+	sum := 0
+	for _, v := range result.children.a {
+		if v.status != DiffStatusSame {
+			result.status = DiffStatusDiff
+		}
+		result.inherit(v)
+		sum += v.diffCount
+	}
+	result.diffCount = sum
+
+	return result
+}
+
+// findMoved pairs the same elements which are not aligned, keys are the position in A and values are in B.
+func findMoved(n int, m int, alignedA map[int]struct{}, alignedB map[int]struct{}, same func(i int, j int) bool) (map[int]int, map[int]int) {
+	movedA := map[int]int{}
+	movedB := map[int]int{}
+
+	for i := 0; i < n; i++ {
+		if _, ok := alignedA[i]; ok {
+			continue
+		}
+
+		for j := 0; j < m; j++ {
+			if _, ok := alignedB[j]; ok {
+				continue
+			}
+			if _, ok := movedB[j]; ok {
+				continue
+			}
+
+			if same(i, j) {
+				movedA[i] = j
+				movedB[j] = i
+
+				break
+			}
+		}
+	}
+
+	return movedA, movedB
+}
+
+type orderedGap struct {
+	r       *runner
+	arrayA  rawTypeArray
+	arrayB  rawTypeArray
+	movedA  map[int]int
+	movedB  map[int]int
+	pair    bool
+	diffAt  func(i int, j int) *diff
+	similar func(d *diff, indexA int, indexB int) bool
+	level   int
+}
+
+// children returns diffs of the elements between aligned ones, A[fromA:toA] and B[fromB:toB].
+func (g *orderedGap) children(fromA int, toA int, fromB int, toB int) diffChildrenArray {
+	children := diffChildrenArray{}
+
+	inserted := []int{}
+	for j := fromB; j < toB; j++ {
+		if _, ok := g.movedB[j]; !ok {
+			inserted = append(inserted, j)
+		}
+	}
+
+	for i := fromA; i < toA; i++ {
+		if _, ok := g.movedA[i]; ok {
+			continue
+		}
+
+		if g.pair && len(inserted) > 0 && g.similar(g.diffAt(i, inserted[0]), i, inserted[0]) {
+			children = append(children, g.diffAt(i, inserted[0]))
+			inserted = inserted[1:]
+
+			continue
+		}
+
+		children = append(children, g.r.performArrayElementDiff(g.arrayA[i], nil, i, -1, g.level))
+	}
+
+	for j := fromB; j < toB; j++ {
+		if i, ok := g.movedB[j]; ok {
+			d := g.diffAt(i, j)
+			d.status = DiffStatusMoved
+			children = append(children, d)
+
+			continue
+		}
+
+		if slices.Contains(inserted, j) {
+			children = append(children, g.r.performArrayElementDiff(nil, g.arrayB[j], -1, j, g.level))
+		}
+	}
+
+	return children
+}
@@ -0,0 +1,150 @@
+package yamldiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+const (
+	PatchOpAdd     = "add"
+	PatchOpRemove  = "remove"
+	PatchOpReplace = "replace"
+)
+
+// PatchOperation is a RFC 6902 JSON Patch operation.
+type PatchOperation struct {
+	Op    string
+	Path  string
+	Value interface{}
+}
+
+type jsonPatchOperation struct {
+	Op    string     `json:"op"`
+	Path  string     `json:"path"`
+	Value *jsonValue `json:"value,omitempty"`
+}
+
+func (o *PatchOperation) MarshalJSON() ([]byte, error) {
+	out := &jsonPatchOperation{
+		Op:   o.Op,
+		Path: o.Path,
+	}
+	if o.Op != PatchOpRemove {
+		out.Value = &jsonValue{v: o.Value}
+	}
+
+	//nolint:wrapcheck
+	return json.Marshal(out)
+}
+
+// JSONPatch returns operations which transform A into B.
+// The whole array is replaced when the paired elements are reordered.
+// If the document exists only in A or B, it's removed or added at the root path "".
+func (y *YamlDiff) JSONPatch() []*PatchOperation {
+	return appendPatch([]*PatchOperation{}, y.Tree())
+}
+
+// EncodeJSONPatch writes JSON Patch of each diff as a line.
+func EncodeJSONPatch(w io.Writer, diffs []*YamlDiff) error {
+	enc := json.NewEncoder(w)
+
+	for _, d := range diffs {
+		if err := enc.Encode(d.JSONPatch()); err != nil {
+			return fmt.Errorf("yamldiff: failed to encode json patch: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func appendPatch(ops []*PatchOperation, n *Node) []*PatchOperation {
+	switch n.Status {
+	case DiffStatusSame:
+		if !isReordered(n) {
+			return ops
+		}
+	case DiffStatus1Missing:
+		return append(ops, &PatchOperation{Op: PatchOpAdd, Path: n.Path.JSONPointer(), Value: n.B})
+	case DiffStatus2Missing:
+		return append(ops, &PatchOperation{Op: PatchOpRemove, Path: n.Path.JSONPointer()})
+	}
+
+	switch {
+	case n.Kind == NodeKindMap && len(n.Children) > 0:
+		for _, c := range n.Children {
+			ops = appendPatch(ops, c)
+		}
+
+		return ops
+
+	case n.Kind == NodeKindArray && len(n.Children) > 0 && isOrderPreserved(n):
+		return appendArrayPatch(ops, n)
+	}
+
+	return append(ops, &PatchOperation{Op: PatchOpReplace, Path: n.Path.JSONPointer(), Value: n.B})
+}
+
+// isReordered reports that array elements in the node are moved.
+func isReordered(n *Node) bool {
+	reordered := false
+	n.Walk(func(c *Node) bool {
+		reordered = reordered || c.Status == DiffStatusMoved
+
+		return !reordered
+	})
+
+	return reordered
+}
+
+// isOrderPreserved reports that paired elements keep their relative order.
+// In this case, removing unpaired A elements then inserting unpaired B elements
+// in ascending order reproduces B.
+func isOrderPreserved(n *Node) bool {
+	paired := []*Node{}
+	for _, c := range n.Children {
+		if c.IndexA >= 0 && c.IndexB >= 0 {
+			paired = append(paired, c)
+		}
+	}
+
+	sort.Slice(paired, func(i, j int) bool { return paired[i].IndexA < paired[j].IndexA })
+
+	for i := 1; i < len(paired); i++ {
+		if paired[i-1].IndexB > paired[i].IndexB {
+			return false
+		}
+	}
+
+	return true
+}
+
+func appendArrayPatch(ops []*PatchOperation, n *Node) []*PatchOperation {
+	removed := []*Node{}
+	added := []*Node{}
+
+	for _, c := range n.Children {
+		switch {
+		case c.IndexB < 0:
+			removed = append(removed, c)
+		case c.IndexA < 0:
+			added = append(added, c)
+		default:
+			ops = appendPatch(ops, c)
+		}
+	}
+
+	// remove from the last element to keep indices of others
+	sort.Slice(removed, func(i, j int) bool { return removed[i].IndexA > removed[j].IndexA })
+	for _, c := range removed {
+		ops = append(ops, &PatchOperation{Op: PatchOpRemove, Path: n.Path.appendIndex(c.IndexA).JSONPointer()})
+	}
+
+	sort.Slice(added, func(i, j int) bool { return added[i].IndexB < added[j].IndexB })
+	for _, c := range added {
+		ops = append(ops, &PatchOperation{Op: PatchOpAdd, Path: n.Path.appendIndex(c.IndexB).JSONPointer(), Value: c.B})
+	}
+
+	return ops
+}
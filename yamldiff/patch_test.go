@@ -0,0 +1,99 @@
+package yamldiff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYamlDiff_JSONPatch(t *testing.T) {
+	tests := map[string]struct {
+		a    string
+		b    string
+		want []*PatchOperation
+	}{
+		"same": {
+			a:    "foo: bar\n",
+			b:    "foo: bar\n",
+			want: []*PatchOperation{},
+		},
+		"map": {
+			a: "foo: bar\nremoved: 1\nnested:\n  a/b: 1\n",
+			b: "foo: baz\nnested:\n  a/b: 2\nadded: [1]\n",
+			want: []*PatchOperation{
+				{Op: PatchOpReplace, Path: "/foo", Value: "baz"},
+				{Op: PatchOpRemove, Path: "/removed"},
+				{Op: PatchOpReplace, Path: "/nested/a~1b", Value: uint64(2)},
+				{Op: PatchOpAdd, Path: "/added", Value: rawTypeArray{uint64(1)}},
+			},
+		},
+		"type changed": {
+			a: "foo: bar\n",
+			b: "foo:\n  bar: baz\n",
+			want: []*PatchOperation{
+				{Op: PatchOpReplace, Path: "/foo", Value: rawTypeMap{{Key: "bar", Value: "baz"}}},
+			},
+		},
+		"array": {
+			a: "list:\n- name: a\n  image: a:1\n- name: b\n- name: c\n",
+			b: "list:\n- name: a\n  image: a:2\n- name: c\n",
+			want: []*PatchOperation{
+				{Op: PatchOpReplace, Path: "/list/0/image", Value: "a:2"},
+				{Op: PatchOpRemove, Path: "/list/1"},
+			},
+		},
+		"array added": {
+			a: "list: [1, 2]\n",
+			b: "list: [1, 2, 3, 4]\n",
+			want: []*PatchOperation{
+				{Op: PatchOpAdd, Path: "/list/2", Value: uint64(3)},
+				{Op: PatchOpAdd, Path: "/list/3", Value: uint64(4)},
+			},
+		},
+		"array reordered": {
+			a: "list: [1, 2, 3]\n",
+			b: "list: [3, 1, 4]\n",
+			want: []*PatchOperation{
+				{Op: PatchOpReplace, Path: "/list", Value: rawTypeArray{uint64(3), uint64(1), uint64(4)}},
+			},
+		},
+		"array only reordered": {
+			a: "nested:\n  list: [a, b, c]\n",
+			b: "nested:\n  list: [b, c, a]\n",
+			want: []*PatchOperation{
+				{Op: PatchOpReplace, Path: "/nested/list", Value: rawTypeArray{"b", "c", "a"}},
+			},
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			yamlA, err := Load(tc.a)
+			require.NoError(t, err)
+
+			yamlB, err := Load(tc.b)
+			require.NoError(t, err)
+
+			diffs := Do(yamlA, yamlB)
+			require.Len(t, diffs, 1)
+
+			assert.Equal(t, tc.want, diffs[0].JSONPatch())
+		})
+	}
+}
+
+func TestEncodeJSONPatch(t *testing.T) {
+	yamlA, err := Load("foo: bar\nnull: 1\n---\nremoved: true\n")
+	require.NoError(t, err)
+
+	yamlB, err := Load("foo: bar\nnull:\n")
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, EncodeJSONPatch(&b, Do(yamlA, yamlB)))
+
+	assert.Equal(t, `[{"op":"replace","path":"/null","value":null}]
+[{"op":"remove","path":""}]
+`, b.String())
+}
@@ -0,0 +1,259 @@
+package yamldiff
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PathElement is a map key or an array index in a Path.
+type PathElement struct {
+	Key     string
+	Index   int
+	IsIndex bool
+}
+
+// Path points a value in a yaml document, e.g. `spec.containers[0].image`.
+type Path []PathElement
+
+func (p Path) appendKey(k string) Path {
+	return append(p[:len(p):len(p)], PathElement{Key: k})
+}
+
+func (p Path) appendIndex(i int) Path {
+	return append(p[:len(p):len(p)], PathElement{Index: i, IsIndex: true})
+}
+
+func (p Path) String() string {
+	var b strings.Builder
+
+	for i, e := range p {
+		switch {
+		case e.IsIndex:
+			b.WriteString("[" + strconv.Itoa(e.Index) + "]")
+		case needsQuote(e.Key):
+			b.WriteString("[" + strconv.Quote(e.Key) + "]")
+		default:
+			if i > 0 {
+				b.WriteString(".")
+			}
+			b.WriteString(e.Key)
+		}
+	}
+
+	return b.String()
+}
+
+// JSONPointer returns RFC 6901 JSON Pointer, e.g. `/spec/containers/0/image`.
+func (p Path) JSONPointer() string {
+	var b strings.Builder
+
+	for _, e := range p {
+		b.WriteString("/")
+		if e.IsIndex {
+			b.WriteString(strconv.Itoa(e.Index))
+
+			continue
+		}
+
+		b.WriteString(jsonPointerEscaper.Replace(e.Key))
+	}
+
+	return b.String()
+}
+
+//nolint:gochecknoglobals
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func needsQuote(k string) bool {
+	return k == "" || strings.ContainsAny(k, ".[]\"*' \t\n")
+}
+
+type patternKind int
+
+const (
+	patternKey patternKind = iota
+	patternIndex
+	patternAnyKey
+	patternAnyIndex
+	patternAnyDescendant
+)
+
+type patternElement struct {
+	kind  patternKind
+	key   string
+	index int
+}
+
+// pathPattern is a parsed path expression like `spec.containers[*].image`.
+// `**` matches any number of keys and indices, e.g. `**.resourceVersion`.
+type pathPattern []patternElement
+
+// parsePathPattern parses the same format as Path.String, with optional leading `.` and wildcards.
+// Malformed brackets are treated as a part of key.
+func parsePathPattern(s string) pathPattern {
+	p := pathPattern{}
+
+	for len(s) > 0 {
+		switch s[0] {
+		case '.':
+			s = s[1:]
+
+		case '[':
+			end := closingBracket(s)
+			if end < 0 {
+				p = append(p, patternElement{kind: patternKey, key: s})
+				s = ""
+
+				continue
+			}
+
+			p = append(p, parseBracket(s[1:end]))
+			s = s[end+1:]
+
+		default:
+			end := strings.IndexAny(s, ".[")
+			if end < 0 {
+				end = len(s)
+			}
+
+			switch s[:end] {
+			case "*":
+				p = append(p, patternElement{kind: patternAnyKey})
+			case "**":
+				p = append(p, patternElement{kind: patternAnyDescendant})
+			default:
+				p = append(p, patternElement{kind: patternKey, key: s[:end]})
+			}
+			s = s[end:]
+		}
+	}
+
+	return p
+}
+
+func closingBracket(s string) int {
+	if !strings.HasPrefix(s, `["`) {
+		return strings.Index(s, "]")
+	}
+
+	for i := 2; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			if i+1 < len(s) && s[i+1] == ']' {
+				return i + 1
+			}
+
+			return -1
+		}
+	}
+
+	return -1
+}
+
+func parseBracket(s string) patternElement {
+	if s == "*" {
+		return patternElement{kind: patternAnyIndex}
+	}
+
+	if i, err := strconv.Atoi(s); err == nil {
+		return patternElement{kind: patternIndex, index: i}
+	}
+
+	if k, err := strconv.Unquote(s); err == nil {
+		return patternElement{kind: patternKey, key: k}
+	}
+
+	return patternElement{kind: patternKey, key: s}
+}
+
+func (p pathPattern) match(path Path) bool {
+	if len(p) == 0 {
+		return len(path) == 0
+	}
+
+	if p[0].kind == patternAnyDescendant {
+		for i := 0; i <= len(path); i++ {
+			if p[1:].match(path[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return len(path) > 0 && p[0].matchElement(path[0]) && p[1:].match(path[1:])
+}
+
+func (e patternElement) matchElement(pe PathElement) bool {
+	switch e.kind {
+	case patternKey:
+		return !pe.IsIndex && pe.Key == e.key
+	case patternIndex:
+		return pe.IsIndex && pe.Index == e.index
+	case patternAnyKey:
+		return !pe.IsIndex
+	case patternAnyIndex:
+		return pe.IsIndex
+	}
+
+	return false
+}
+
+// path converts the pattern to Path, false if it has wildcards.
+func (p pathPattern) path() (Path, bool) {
+	path := Path{}
+	for _, e := range p {
+		switch e.kind {
+		case patternKey:
+			path = path.appendKey(e.key)
+		case patternIndex:
+			path = path.appendIndex(e.index)
+		default:
+			return nil, false
+		}
+	}
+
+	return path, true
+}
+
+// lookup returns the value at the path, wildcards are not supported.
+func lookup(v rawType, p pathPattern) (rawType, bool) {
+	for _, e := range p {
+		switch e.kind {
+		case patternKey:
+			m, ok := tryMap(v)
+			if !ok {
+				return nil, false
+			}
+
+			found := false
+			for _, item := range m {
+				if k, ok := item.Key.(string); ok && k == e.key {
+					v = item.Value
+					found = true
+
+					break
+				}
+			}
+
+			if !found {
+				return nil, false
+			}
+
+		case patternIndex:
+			a, ok := tryArray(v)
+			if !ok || e.index < 0 || len(a) <= e.index {
+				return nil, false
+			}
+
+			v = a[e.index]
+
+		default:
+			return nil, false
+		}
+	}
+
+	return v, true
+}
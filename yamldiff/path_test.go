@@ -0,0 +1,155 @@
+package yamldiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPath_String(t *testing.T) {
+	tests := map[string]struct {
+		path Path
+		want string
+	}{
+		"root": {
+			path: Path{},
+			want: "",
+		},
+		"keys": {
+			path: Path{}.appendKey("spec").appendKey("replicas"),
+			want: "spec.replicas",
+		},
+		"keys and index": {
+			path: Path{}.appendKey("containers").appendIndex(0).appendKey("image"),
+			want: "containers[0].image",
+		},
+		"index at root": {
+			path: Path{}.appendIndex(1).appendKey("name"),
+			want: "[1].name",
+		},
+		"quoted key": {
+			path: Path{}.appendKey("labels").appendKey("app.kubernetes.io/name"),
+			want: `labels["app.kubernetes.io/name"]`,
+		},
+		"empty key": {
+			path: Path{}.appendKey(""),
+			want: `[""]`,
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.path.String())
+		})
+	}
+}
+
+func TestPath_JSONPointer(t *testing.T) {
+	tests := map[string]struct {
+		path Path
+		want string
+	}{
+		"root": {
+			path: Path{},
+			want: "",
+		},
+		"keys and index": {
+			path: Path{}.appendKey("containers").appendIndex(0).appendKey("image"),
+			want: "/containers/0/image",
+		},
+		"escaped key": {
+			path: Path{}.appendKey("a/b").appendKey("c~d"),
+			want: "/a~1b/c~0d",
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.path.JSONPointer())
+		})
+	}
+}
+
+func TestPathPattern_match(t *testing.T) {
+	containerImage := Path{}.appendKey("spec").appendKey("containers").appendIndex(1).appendKey("image")
+
+	tests := map[string]struct {
+		pattern string
+		path    Path
+		want    bool
+	}{
+		"exact": {
+			pattern: "spec.containers[1].image",
+			path:    containerImage,
+			want:    true,
+		},
+		"leading dot": {
+			pattern: ".spec.containers[1].image",
+			path:    containerImage,
+			want:    true,
+		},
+		"any index": {
+			pattern: "spec.containers[*].image",
+			path:    containerImage,
+			want:    true,
+		},
+		"any key": {
+			pattern: "*.containers[*].*",
+			path:    containerImage,
+			want:    true,
+		},
+		"different index": {
+			pattern: "spec.containers[0].image",
+			path:    containerImage,
+			want:    false,
+		},
+		"any key doesn't match index": {
+			pattern: "spec.containers.*.image",
+			path:    containerImage,
+			want:    false,
+		},
+		"shorter": {
+			pattern: "spec.containers",
+			path:    containerImage,
+			want:    false,
+		},
+		"quoted key": {
+			pattern: `metadata.labels["app.kubernetes.io/name"]`,
+			path:    Path{}.appendKey("metadata").appendKey("labels").appendKey("app.kubernetes.io/name"),
+			want:    true,
+		},
+		"malformed bracket": {
+			pattern: "foo[bar",
+			path:    Path{}.appendKey("foo").appendKey("[bar"),
+			want:    true,
+		},
+		"any descendants": {
+			pattern: "**.image",
+			path:    containerImage,
+			want:    true,
+		},
+		"any descendants in the middle": {
+			pattern: "spec.**.image",
+			path:    containerImage,
+			want:    true,
+		},
+		"any descendants matches nothing": {
+			pattern: "spec.**.containers[1].image",
+			path:    containerImage,
+			want:    true,
+		},
+		"any descendants with different leaf": {
+			pattern: "**.name",
+			path:    containerImage,
+			want:    false,
+		},
+		"root": {
+			pattern: "",
+			path:    Path{},
+			want:    true,
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			assert.Equal(t, tc.want, parsePathPattern(tc.pattern).match(tc.path))
+		})
+	}
+}
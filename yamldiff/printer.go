@@ -3,13 +3,99 @@ package yamldiff
 import (
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
 )
 
 const (
 	indentString = "  "
+
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiDim    = "\x1b[2m"
+	ansiNormal = "\x1b[22m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+)
+
+// Highlight is the style to highlight the changed part of modified scalars.
+type Highlight int
+
+const (
+	HighlightNone    Highlight = 0
+	HighlightANSI    Highlight = 1 // reverse video with ANSI escape sequences
+	HighlightMarkers Highlight = 2 // `[-removed-]` and `{+added+}`
 )
 
+type dumpOptions struct {
+	highlight Highlight
+	color     bool
+
+	// print only changes and the surrounding siblings if limitContext
+	limitContext bool
+	context      int
+
+	sideBySide bool
+	width      int
+
+	yamlScalars bool
+
+	originalSource bool
+}
+
+type DumpOptionFunc func(o *dumpOptions)
+
+// IntralineHighlight highlights the changed characters of modified scalars, e.g. `my-app:1.[-0-].0` and `my-app:1.{+1+}.0`.
+func IntralineHighlight(h Highlight) DumpOptionFunc {
+	return func(o *dumpOptions) {
+		o.highlight = h
+	}
+}
+
+// Colorize prints removals in red, additions in green, moves in yellow, unchanged lines dimmed and map keys in bold
+// with ANSI escape sequences.
+func Colorize() DumpOptionFunc {
+	return func(o *dumpOptions) {
+		o.color = true
+	}
+}
+
+// Context prints only changed values and n unchanged siblings around them,
+// and the rest are collapsed into markers like `... (42 unchanged fields)`.
+// Values which have moved array elements or reported coerced scalars are changed.
+func Context(n int) DumpOptionFunc {
+	return func(o *dumpOptions) {
+		o.limitContext = true
+		o.context = n
+	}
+}
+
+// YAMLScalars prints values with YAML scalar encoding, e.g. `1.5`, `"8080"` and block scalars for multi-line strings,
+// so the lines can be copied back into a YAML file.
+func YAMLScalars() DumpOptionFunc {
+	return func(o *dumpOptions) {
+		o.yamlScalars = true
+	}
+}
+
+type printer struct {
+	w      io.Writer
+	option dumpOptions
+
+	// collected lines for side-by-side output
+	lines []*printedLine
+
+	// source nodes and the current paths of A and B for OriginalSource
+	sourceA sourceIndex
+	sourceB sourceIndex
+	pathA   Path
+	pathB   Path
+}
+
 type sortedChildItem struct {
 	k string
 	v *diff
@@ -19,160 +105,576 @@ func indent(level int) string {
 	return strings.Repeat(indentString, level)
 }
 
-func dumpData(b io.Writer, diffPrefix string, level int, v rawType) {
+func (p *printer) dumpData(diffPrefix string, level int, v rawType) {
 	if t, ok := tryMap(v); ok {
-		dumpMap(b, diffPrefix, level, t)
+		p.dumpMap(diffPrefix, level, t)
 
 		return
 	}
 
 	if t, ok := tryArray(v); ok {
-		dumpArray(b, diffPrefix, level, t)
+		p.dumpArray(diffPrefix, level, t)
 
 		return
 	}
 
-	dumpPrimitive(b, diffPrefix, level, "", v)
+	p.dumpPrimitive(diffPrefix, level, "", v)
 }
 
-func dumpMap(b io.Writer, diffPrefix string, level int, m rawTypeMap) {
+func (p *printer) dumpMap(diffPrefix string, level int, m rawTypeMap) {
 	for _, v := range m {
 		k, ok := v.Key.(string)
-		if !ok {
-			k = ""
+		if ok {
+			k = p.formatKey(k)
 		}
 
-		dumpMapItem(b, diffPrefix, level, k, v)
+		p.dumpMapItem(diffPrefix, level, k, v)
 	}
 }
 
-func dumpArray(b io.Writer, diffPrefix string, level int, m rawTypeArray) {
+func (p *printer) dumpArray(diffPrefix string, level int, m rawTypeArray) {
 	for _, v := range m {
-		dumpArrayItem(b, diffPrefix, level, v)
+		p.dumpArrayItem(diffPrefix, level, v)
 	}
 }
 
-func dumpArrayItem(b io.Writer, diffPrefix string, level int, v rawType) {
+func (p *printer) dumpArrayItem(diffPrefix string, level int, v rawType) {
 	if t, ok := tryMap(v); ok {
-		fmt.Fprintf(b, "%s %s-\n", diffPrefix, indent(level))
-		dumpData(b, diffPrefix, level+1, t)
+		p.line(diffPrefix, level, "", "-"+p.emptyCollection(t))
+		p.dumpData(diffPrefix, level+1, t)
 
 		return
 	}
 
 	if t, ok := tryArray(v); ok {
-		fmt.Fprintf(b, "%s %s-\n", diffPrefix, indent(level))
-		dumpData(b, diffPrefix, level+1, t)
+		p.line(diffPrefix, level, "", "-"+p.emptyCollection(t))
+		p.dumpData(diffPrefix, level+1, t)
 
 		return
 	}
 
-	dumpPrimitive(b, diffPrefix, level, "- ", v)
+	p.dumpPrimitive(diffPrefix, level, "- ", v)
 }
 
-func dumpMapItem(b io.Writer, diffPrefix string, level int, k string, v rawType) {
+func (p *printer) dumpMapItem(diffPrefix string, level int, k string, v rawType) {
 	if t, ok := tryMap(v); ok {
-		fmt.Fprintf(b, "%s %s%s:\n", diffPrefix, indent(level), k)
-		dumpData(b, diffPrefix, level+1, t)
+		p.line(diffPrefix, level, k, ":"+p.emptyCollection(t))
+		p.dumpData(diffPrefix, level+1, t)
 
 		return
 	}
 
 	if t, ok := tryArray(v); ok {
-		fmt.Fprintf(b, "%s %s%s:\n", diffPrefix, indent(level), k)
-		dumpData(b, diffPrefix, level+1, t)
+		p.line(diffPrefix, level, k, ":"+p.emptyCollection(t))
+		p.dumpData(diffPrefix, level+1, t)
 
 		return
 	}
 
 	if t, ok := tryMapItem(v); ok {
-		dumpMapItem(b, diffPrefix, level, k, t.Value)
+		p.dumpMapItem(diffPrefix, level, k, t.Value)
 
 		return
 	}
 
 	if v == nil {
-		dumpPrimitive(b, diffPrefix, level, fmt.Sprintf("%s:", k), v)
+		p.line(diffPrefix, level, k, ":")
+
+		return
+	}
+
+	p.lineValue(diffPrefix, level, k, ": ", v)
+}
+
+// emptyCollection returns ` {}` or ` []` for the empty map or array with YAMLScalars, so that it isn't read as null.
+func (p *printer) emptyCollection(v rawType) string {
+	if !p.option.yamlScalars {
+		return ""
+	}
+
+	if t, ok := tryMap(v); ok && len(t) == 0 {
+		return " {}"
+	}
+
+	if t, ok := tryArray(v); ok && len(t) == 0 {
+		return " []"
+	}
+
+	return ""
+}
+
+// emptyCollections returns emptyCollection if both sides are the same empty collection.
+func (p *printer) emptyCollections(d *diff) string {
+	if e := p.emptyCollection(d.a); e == p.emptyCollection(d.b) {
+		return e
+	}
+
+	return ""
+}
+
+func (p *printer) dumpPrimitive(diffPrefix string, level int, somethingPrefix string, v rawType) {
+	p.lineValue(diffPrefix, level, "", somethingPrefix, v)
+}
+
+// lineValue prints a line ends with the value, the rest lines of block scalar are indented.
+func (p *printer) lineValue(diffPrefix string, level int, key string, somethingPrefix string, v rawType) {
+	lines := strings.Split(p.formatPrimitive(v), "\n")
+
+	p.line(diffPrefix, level, key, somethingPrefix+lines[0])
+	for _, l := range lines[1:] {
+		p.line(diffPrefix, level+1, "", strings.TrimPrefix(l, indentString))
+	}
+}
+
+// formatKey returns the map key to print, an empty key is quoted so that it's not read as no key.
+func (p *printer) formatKey(k string) string {
+	if p.option.yamlScalars {
+		return formatYAMLScalar(k)
+	}
+
+	if k == "" {
+		return `""`
+	}
+
+	return k
+}
+
+// line prints a line of the diff, the key is printed as a map key if it's not empty.
+// The key is already formatted by formatKey, so an empty key means the line is not a map field.
+func (p *printer) line(diffPrefix string, level int, key string, rest string) {
+	if p.option.sideBySide {
+		p.lines = append(p.lines, &printedLine{diffPrefix: diffPrefix, text: indent(level) + key + rest})
+
+		return
+	}
+
+	if !p.option.color {
+		fmt.Fprintf(p.w, "%s %s%s%s\n", diffPrefix, indent(level), key, rest)
 
 		return
 	}
 
-	dumpPrimitive(b, diffPrefix, level, fmt.Sprintf("%s: ", k), v)
+	c := lineColor(diffPrefix)
+	if key != "" {
+		key = ansiBold + key + ansiNormal + c
+	}
+
+	fmt.Fprintf(p.w, "%s%s %s%s%s%s\n", c, diffPrefix, indent(level), key, rest, ansiReset)
+}
+
+func lineColor(diffPrefix string) string {
+	switch diffPrefix {
+	case "-":
+		return ansiRed
+	case "+":
+		return ansiGreen
+	case "~":
+		return ansiYellow
+	}
+
+	return ansiDim
 }
 
-func dumpPrimitive(b io.Writer, diffPrefix string, level int, somethingPrefix string, v rawType) {
+func (p *printer) formatPrimitive(v rawType) string {
 	switch v.(type) {
 	case nil, _missingKey:
-		fmt.Fprintf(b, "%s %s%s\n", diffPrefix, indent(level), somethingPrefix)
+		return ""
+	}
+
+	if p.option.yamlScalars {
+		return formatYAMLScalar(v)
+	}
+
+	return formatPrimitive(v)
+}
+
+// formatYAMLScalar encodes the value as YAML, it can be multiple lines for block scalar.
+// Strings with surrounding whitespace are double quoted, because the encoder doesn't quote the leading tab.
+func formatYAMLScalar(v rawType) string {
+	if s, ok := v.(string); ok && s != strings.TrimSpace(s) && !strings.Contains(s, "\n") {
+		return strconv.Quote(s)
+	}
+
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return formatPrimitive(v)
+	}
+
+	return strings.TrimSuffix(string(b), "\n")
+}
+
+func formatPrimitive(v rawType) string {
+	switch v.(type) {
+	case nil, _missingKey:
+		return ""
 	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
-		fmt.Fprintf(b, "%s %s%s%d\n", diffPrefix, indent(level), somethingPrefix, v)
+		return fmt.Sprintf("%d", v)
 	case float32, float64:
-		fmt.Fprintf(b, "%s %s%s%f\n", diffPrefix, indent(level), somethingPrefix, v)
+		return fmt.Sprintf("%f", v)
 	case string:
 		// try escape special characters
-		fmt.Fprintf(b, "%s %s%s%#v\n", diffPrefix, indent(level), somethingPrefix, v)
+		return fmt.Sprintf("%#v", v)
 	default:
-		fmt.Fprintf(b, "%s %s%s%#v\n", diffPrefix, indent(level), somethingPrefix, v)
+		return fmt.Sprintf("%#v", v)
 	}
 }
 
-func (d *diff) dump(b io.Writer, level int) {
+// dumpModified prints modified scalars with the changed part highlighted, false if it's disabled or the values are not scalars.
+func (p *printer) dumpModified(level int, key string, somethingPrefix string, rawA rawType, rawB rawType) bool {
+	if p.option.highlight == HighlightNone || p.option.originalSource || !isScalar(rawA) || !isScalar(rawB) {
+		return false
+	}
+
+	strA := []rune(p.formatPrimitive(rawA))
+	strB := []rune(p.formatPrimitive(rawB))
+	if strings.ContainsRune(string(strA), '\n') || strings.ContainsRune(string(strB), '\n') {
+		return false
+	}
+	prefix, suffix := commonPrefix(strA, strB), commonSuffix(strA, strB)
+
+	// the prefix and suffix can't overlap
+	if limit := min(len(strA), len(strB)) - prefix; suffix > limit {
+		suffix = limit
+	}
+
+	p.line("-", level, key, somethingPrefix+p.highlight(strA, prefix, len(strA)-suffix, "-"))
+	p.line("+", level, key, somethingPrefix+p.highlight(strB, prefix, len(strB)-suffix, "+"))
+
+	return true
+}
+
+func (p *printer) highlight(s []rune, start int, end int, diffPrefix string) string {
+	if start == end {
+		return string(s)
+	}
+
+	var open, closing string
+	switch {
+	case p.option.highlight == HighlightANSI:
+		open, closing = "\x1b[7m", "\x1b[27m"
+	case diffPrefix == "-":
+		open, closing = "[-", "-]"
+	default:
+		open, closing = "{+", "+}"
+	}
+
+	return string(s[:start]) + open + string(s[start:end]) + closing + string(s[end:])
+}
+
+func isScalar(v rawType) bool {
+	switch v.(type) {
+	case nil, _missingKey, rawTypeMap, rawTypeArray:
+		return false
+	}
+
+	return true
+}
+
+// dumpLineDiff prints multi-line strings as nested unified diff, false if the values are not multi-line strings.
+func (p *printer) dumpLineDiff(level int, key string, somethingPrefix string, rawA rawType, rawB rawType) bool {
+	hunks := lineHunks(rawA, rawB)
+	if len(hunks) == 0 {
+		return false
+	}
+
+	p.line(" ", level, key, somethingPrefix+"|")
+	for _, h := range hunks {
+		p.line(" ", level+1, "", h.Header())
+		for _, l := range h.Lines {
+			p.line(l.Op.prefix(), level+1, "", l.Text)
+		}
+	}
+
+	return true
+}
+
+func (p *printer) dump(d *diff, level int) {
 	if d.children != nil {
-		d.dumpTryArray(b, level)
-		d.dumpTryMap(b, level)
+		p.dumpTryArray(d, level)
+		p.dumpTryMap(d, level)
 
 		return
 	}
 
 	switch d.status {
 	case DiffStatusSame:
-		dumpData(b, " ", level, d.a)
+		p.dumpValue(" ", level, d.a)
 	case DiffStatusDiff:
+		if p.dumpLineDiff(level, "", "", d.a, d.b) || p.dumpModified(level, "", "", d.a, d.b) {
+			return
+		}
 		if d.a != nil {
-			dumpData(b, "-", level, d.a)
+			p.dumpValue("-", level, d.a)
 		}
 		if d.b != nil {
-			dumpData(b, "+", level, d.b)
+			p.dumpValue("+", level, d.b)
 		}
 	case DiffStatus1Missing:
-		dumpData(b, "+", level, d.b)
+		p.dumpValue("+", level, d.b)
 	case DiffStatus2Missing:
-		dumpData(b, "-", level, d.a)
+		p.dumpValue("-", level, d.a)
+	}
+}
+
+func (p *printer) dumpValue(diffPrefix string, level int, v rawType) {
+	if !p.dumpSource(diffPrefix, level, false) {
+		p.dumpData(diffPrefix, level, v)
+	}
+}
+
+func (p *printer) dumpArrayEntry(diffPrefix string, level int, v rawType) {
+	if !p.dumpSource(diffPrefix, level, true) {
+		p.dumpArrayItem(diffPrefix, level, v)
+	}
+}
+
+func (p *printer) dumpMapEntry(diffPrefix string, level int, k string, v rawType) {
+	if !p.dumpSource(diffPrefix, level, false) {
+		p.dumpMapItem(diffPrefix, level, k, v)
 	}
 }
 
-func (d *diff) dumpTryArray(b io.Writer, level int) {
+func (p *printer) dumpTryArray(d *diff, level int) {
 	if d.children.a == nil {
 		return
 	}
 
+	children := diffChildrenArray{}
 	for _, v := range d.children.a {
+		if !v.ignored {
+			children = append(children, v)
+		}
+	}
+
+	changed := make([]bool, len(children))
+	for i, v := range children {
+		changed[i] = v.changed()
+	}
+
+	visible := p.visible(changed)
+	hidden := 0
+
+	parentA, parentB := p.pathA, p.pathB
+	defer func() { p.pathA, p.pathB = parentA, parentB }()
+
+	for i, v := range children {
+		if !visible[i] {
+			hidden++
+
+			continue
+		}
+
+		p.dumpCollapsed(level, hidden, "item")
+		hidden = 0
+		p.pathA, p.pathB = parentA.appendIndex(v.indexA), parentB.appendIndex(v.indexB)
+
+		if v.status == DiffStatusMoved {
+			p.line("~", level, "", fmt.Sprintf("# moved from [%d] to [%d]", v.indexA, v.indexB))
+			p.dumpArrayEntry("~", level, v.b)
+
+			continue
+		}
+
+		if p.option.limitContext && !changed[i] {
+			p.dumpUnchanged(level, false, "", v.a)
+
+			continue
+		}
+
+		// the same value is printed as it is in the source
+		if !v.changed() && p.dumpSource(" ", level, true) {
+			continue
+		}
+
 		if v.children != nil && (v.children.a != nil || v.children.m != nil) {
-			fmt.Fprintf(b, "  %s-\n", indent(level))
-			v.dump(b, level+1)
+			// the source line of flow style has all children, so it's printed as a whole
+			if p.isFlowSource() {
+				p.dumpArrayEntry("-", level, v.a)
+				p.dumpArrayEntry("+", level, v.b)
+
+				continue
+			}
+
+			p.line(" ", level, "", p.dumpSourceItemHead(" ", level)+p.emptyCollections(v))
+			p.dump(v, level+1)
 
 			continue
 		}
 
 		switch v.status {
 		case DiffStatusSame:
-			dumpArrayItem(b, " ", level, v.a)
+			p.dumpArrayEntry(" ", level, v.a)
 		case DiffStatusDiff:
-			dumpArrayItem(b, "-", level, v.a)
-			dumpArrayItem(b, "+", level, v.b)
+			if p.dumpLineDiff(level, "", "- ", v.a, v.b) || p.dumpModified(level, "", "- ", v.a, v.b) {
+				continue
+			}
+			p.dumpArrayEntry("-", level, v.a)
+			p.dumpArrayEntry("+", level, v.b)
 		case DiffStatus1Missing:
-			dumpArrayItem(b, "+", level, v.b)
+			p.dumpArrayEntry("+", level, v.b)
 		case DiffStatus2Missing:
-			dumpArrayItem(b, "-", level, v.a)
+			p.dumpArrayEntry("-", level, v.a)
 		}
 	}
+
+	p.dumpCollapsed(level, hidden, "item")
 }
 
-func (d *diff) dumpTryMap(b io.Writer, level int) {
+func (p *printer) dumpTryMap(d *diff, level int) {
 	if d.children.m == nil {
 		return
 	}
 
+	children := d.sortedMapChildren()
+
+	changed := make([]bool, len(children))
+	for i, r := range children {
+		changed[i] = r.v.changed()
+	}
+
+	visible := p.visible(changed)
+	hidden := 0
+
+	parentA, parentB := p.pathA, p.pathB
+	defer func() { p.pathA, p.pathB = parentA, parentB }()
+
+	merged := map[ast.Node]bool{}
+
+	for i, r := range children {
+		if !visible[i] {
+			hidden++
+
+			continue
+		}
+
+		p.dumpCollapsed(level, hidden, "field")
+		hidden = 0
+		p.pathA, p.pathB = parentA.appendKey(r.k), parentB.appendKey(r.k)
+		key := p.formatKey(r.k)
+
+		if !r.v.changed() && p.dumpSourceMerge(level, merged) {
+			continue
+		}
+
+		if p.option.limitContext && !changed[i] {
+			p.dumpUnchanged(level, true, key, r.v.a)
+
+			continue
+		}
+
+		// the same value is printed as it is in the source
+		if !r.v.changed() && p.dumpSource(" ", level, false) {
+			continue
+		}
+
+		if r.v.children != nil && (r.v.children.a != nil || r.v.children.m != nil) {
+			// the source line of flow style has all children, so it's printed as a whole
+			if p.isFlowSource() {
+				p.dumpMapEntry("-", level, key, r.v.a)
+				p.dumpMapEntry("+", level, key, r.v.b)
+
+				continue
+			}
+
+			if !p.dumpSourceHead(level) {
+				p.line(" ", level, key, ":"+p.emptyCollections(r.v))
+			}
+			p.dump(r.v, level+1)
+
+			continue
+		}
+
+		switch r.v.status {
+		case DiffStatusSame:
+			p.dumpMapEntry(" ", level, key, r.v.a)
+		case DiffStatusDiff:
+			if p.dumpLineDiff(level, key, ": ", r.v.a, r.v.b) || p.dumpModified(level, key, ": ", r.v.a, r.v.b) {
+				continue
+			}
+			p.dumpMapEntry("-", level, key, r.v.a)
+			p.dumpMapEntry("+", level, key, r.v.b)
+		case DiffStatus1Missing:
+			p.dumpMapEntry("+", level, key, r.v.b)
+		case DiffStatus2Missing:
+			p.dumpMapEntry("-", level, key, r.v.a)
+		}
+	}
+
+	p.dumpCollapsed(level, hidden, "field")
+}
+
+// visible tells which siblings are printed, the changed ones and the context around them.
+func (p *printer) visible(changed []bool) []bool {
+	visible := make([]bool, len(changed))
+	for i, c := range changed {
+		if !p.option.limitContext {
+			visible[i] = true
+
+			continue
+		}
+
+		if !c {
+			continue
+		}
+
+		for j := max(i-p.option.context, 0); j <= i+p.option.context && j < len(changed); j++ {
+			visible[j] = true
+		}
+	}
+
+	return visible
+}
+
+// dumpUnchanged prints the unchanged sibling of the changes, the nested values are collapsed.
+// Scalars and flow style values are printed as the source, and collapsed values keep the source key line.
+func (p *printer) dumpUnchanged(level int, mapItem bool, key string, v rawType) {
+	m, isMap := tryMap(v)
+	a, isArray := tryArray(v)
+	collapsed := (isMap && len(m) > 0) || (isArray && len(a) > 0)
+
+	if (!collapsed || p.isFlowSource()) && p.dumpSource(" ", level, !mapItem) {
+		return
+	}
+
+	switch {
+	case !collapsed && mapItem:
+		p.dumpMapItem(" ", level, key, v)
+
+		return
+	case !collapsed:
+		p.dumpArrayItem(" ", level, v)
+
+		return
+	case mapItem:
+		if !p.dumpSourceHead(level) {
+			p.line(" ", level, key, ":")
+		}
+	default:
+		p.line(" ", level, "", p.dumpSourceItemHead(" ", level))
+	}
+
+	if isMap {
+		p.dumpCollapsed(level+1, len(m), "field")
+	} else {
+		p.dumpCollapsed(level+1, len(a), "item")
+	}
+}
+
+// dumpCollapsed prints the marker of hidden siblings, unit is singular like "field".
+func (p *printer) dumpCollapsed(level int, hidden int, unit string) {
+	if hidden == 0 {
+		return
+	}
+
+	if hidden > 1 {
+		unit += "s"
+	}
+
+	p.line(" ", level, "", fmt.Sprintf("... (%d unchanged %s)", hidden, unit))
+}
+
+// sortedMapChildren returns map children ordered by A's keys, then B's keys.
+func (d *diff) sortedMapChildren() []*sortedChildItem {
 	sortedChildren := []*sortedChildItem{}
 	checked := map[string]struct{}{}
 
@@ -214,32 +716,33 @@ func (d *diff) dumpTryMap(b io.Writer, level int) {
 		})
 	}
 
-	for _, r := range sortedChildren {
-		if r.v.children != nil && (r.v.children.a != nil || r.v.children.m != nil) {
-			fmt.Fprintf(b, "  %s%s:\n", indent(level), r.k)
-			r.v.dump(b, level+1)
+	return sortedChildren
+}
 
-			continue
-		}
+func (d *diff) Dump() string {
+	return d.DumpWith()
+}
 
-		switch r.v.status {
-		case DiffStatusSame:
-			dumpMapItem(b, " ", level, r.k, r.v.a)
-		case DiffStatusDiff:
-			dumpMapItem(b, "-", level, r.k, r.v.a)
-			dumpMapItem(b, "+", level, r.k, r.v.b)
-		case DiffStatus1Missing:
-			dumpMapItem(b, "+", level, r.k, r.v.b)
-		case DiffStatus2Missing:
-			dumpMapItem(b, "-", level, r.k, r.v.a)
-		}
-	}
+func (d *diff) DumpWith(options ...DumpOptionFunc) string {
+	return d.dumpWith(nil, nil, options)
 }
 
-func (d *diff) Dump() string {
+func (d *diff) dumpWith(sourceA ast.Node, sourceB ast.Node, options []DumpOptionFunc) string {
 	var b strings.Builder
 
-	d.dump(&b, d.treeLevel)
+	p := &printer{
+		w:       &b,
+		sourceA: newSourceIndex(sourceA),
+		sourceB: newSourceIndex(sourceB),
+	}
+	for _, o := range options {
+		o(&p.option)
+	}
+
+	p.dump(d, d.treeLevel)
+	if p.option.sideBySide {
+		p.writeSideBySide(&b)
+	}
 
 	return b.String()
 }
@@ -61,6 +61,14 @@ func Test_diff_Dump(t *testing.T) {
 				},
 				want: "- false\n+ true\n",
 			},
+			"diff but multi-line string": {
+				d: &diff{
+					a:      "foo\nbar\n",
+					b:      "foo\nbaz\n",
+					status: DiffStatusDiff,
+				},
+				want: "  |\n    @@ -1,2 +1,2 @@\n    foo\n-   bar\n+   baz\n",
+			},
 		},
 		"map": {
 			"simple": {
@@ -290,6 +298,21 @@ func Test_diff_Dump(t *testing.T) {
 				},
 				want: "- - \"bar\"\n",
 			},
+			"moved": {
+				d: &diff{
+					children: &diffChildren{
+						a: diffChildrenArray{
+							{a: 2, b: 2, status: DiffStatusMoved, treeLevel: 1, indexA: 1, indexB: 0},
+							{a: 1, b: 1, status: DiffStatusSame, treeLevel: 1, indexA: 0, indexB: 1},
+						},
+					},
+					status: DiffStatusSame,
+				},
+				want: `
+~ # moved from [1] to [0]
+~ - 2
+  - 1`,
+			},
 			"complicated": {
 				d: &diff{
 					children: &diffChildren{
@@ -347,3 +370,274 @@ func Test_diff_Dump(t *testing.T) {
 		})
 	}
 }
+
+func Test_diff_DumpWith_intralineHighlight(t *testing.T) {
+	d := &diff{
+		children: &diffChildren{
+			m: diffChildrenMap{
+				"image": {a: "my-app:1.0.0", b: "my-app:1.1.0", status: DiffStatusDiff, diffCount: 3, treeLevel: 1},
+				"port":  {a: 80, b: 8080, status: DiffStatusDiff, diffCount: 2, treeLevel: 1},
+				"tag":   {a: "aa", b: "aaa", status: DiffStatusDiff, diffCount: 1, treeLevel: 1},
+			},
+		},
+		a: rawTypeMap{
+			yaml.MapItem{Key: "image", Value: "my-app:1.0.0"},
+			yaml.MapItem{Key: "port", Value: 80},
+			yaml.MapItem{Key: "tag", Value: "aa"},
+		},
+		status: DiffStatusDiff,
+	}
+
+	tests := map[string]struct {
+		highlight Highlight
+		want      string
+	}{
+		"none": {
+			highlight: HighlightNone,
+			want: `- image: "my-app:1.0.0"
++ image: "my-app:1.1.0"
+- port: 80
++ port: 8080
+- tag: "aa"
++ tag: "aaa"
+`,
+		},
+		"markers": {
+			highlight: HighlightMarkers,
+			want: `- image: "my-app:1.[-0-].0"
++ image: "my-app:1.{+1+}.0"
+- port: 80
++ port: 80{+80+}
+- tag: "aa"
++ tag: "aa{+a+}"
+`,
+		},
+		"ansi": {
+			highlight: HighlightANSI,
+			want: "- image: \"my-app:1.\x1b[7m0\x1b[27m.0\"\n+ image: \"my-app:1.\x1b[7m1\x1b[27m.0\"\n" +
+				"- port: 80\n+ port: 80\x1b[7m80\x1b[27m\n" +
+				"- tag: \"aa\"\n+ tag: \"aa\x1b[7ma\x1b[27m\"\n",
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			assert.Equal(t, tc.want, d.DumpWith(IntralineHighlight(tc.highlight)))
+		})
+	}
+}
+
+func Test_diff_DumpWith_colorize(t *testing.T) {
+	d := &diff{
+		children: &diffChildren{
+			m: diffChildrenMap{
+				"name": {a: "app", b: "app", status: DiffStatusSame, treeLevel: 1},
+				"port": {a: 80, b: 8080, status: DiffStatusDiff, diffCount: 2, treeLevel: 1},
+			},
+		},
+		a: rawTypeMap{
+			yaml.MapItem{Key: "name", Value: "app"},
+			yaml.MapItem{Key: "port", Value: 80},
+		},
+		status: DiffStatusDiff,
+	}
+
+	assert.Equal(
+		t,
+		"\x1b[2m  \x1b[1mname\x1b[22m\x1b[2m: \"app\"\x1b[0m\n"+
+			"\x1b[31m- \x1b[1mport\x1b[22m\x1b[31m: 80\x1b[0m\n"+
+			"\x1b[32m+ \x1b[1mport\x1b[22m\x1b[32m: 8080\x1b[0m\n",
+		d.DumpWith(Colorize()),
+	)
+}
+
+func Test_diff_DumpWith_context(t *testing.T) {
+	yamlA := mustLoad(t, `
+metadata:
+  name: app
+  labels:
+    app: app
+spec:
+  a: 1
+  b: 2
+  c: 3
+  d: 4
+  e: 5
+  f: 6
+  containers:
+  - name: app
+    image: app:1
+  - name: sidecar
+    image: sidecar:1
+`)
+	yamlB := mustLoad(t, `
+metadata:
+  name: app
+  labels:
+    app: app
+spec:
+  a: 1
+  b: 2
+  c: 3
+  d: 40
+  e: 5
+  f: 6
+  containers:
+  - name: app
+    image: app:2
+  - name: sidecar
+    image: sidecar:1
+`)
+
+	diffs := Do(yamlA, yamlB)
+	assert.Len(t, diffs, 1)
+
+	tests := map[string]struct {
+		n    int
+		want string
+	}{
+		"no context": {
+			n: 0,
+			want: `
+  ... (1 unchanged field)
+  spec:
+    ... (3 unchanged fields)
+-   d: 4
++   d: 40
+    ... (2 unchanged fields)
+    containers:
+      ... (1 unchanged item)
+      -
+        ... (1 unchanged field)
+-       image: "app:1"
++       image: "app:2"
+`,
+		},
+		"context": {
+			n: 1,
+			want: `
+  metadata:
+    ... (2 unchanged fields)
+  spec:
+    ... (2 unchanged fields)
+    c: 3
+-   d: 4
++   d: 40
+    e: 5
+    f: 6
+    containers:
+      -
+        ... (2 unchanged fields)
+      -
+        name: "app"
+-       image: "app:1"
++       image: "app:2"
+`,
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			assert.Equal(t, strings.TrimPrefix(tc.want, "\n"), diffs[0].DumpWith(Context(tc.n)))
+		})
+	}
+}
+
+func Test_diff_DumpWith_context_movedAndCoerced(t *testing.T) {
+	yamlA := mustLoad(t, "name: app\nlist: [a, b]\nport: \"8080\"\n")
+	yamlB := mustLoad(t, "name: app\nlist: [b, a]\nport: 8080\n")
+
+	diffs := Do(yamlA, yamlB, ReportCoercedTypes())
+	assert.Len(t, diffs, 1)
+
+	want := `
+  ... (1 unchanged field)
+  list:
+~   # moved from [0] to [1]
+~   - "a"
+    ... (1 unchanged item)
+  port: "8080"
+`
+	assert.Equal(t, strings.TrimPrefix(want, "\n"), diffs[0].DumpWith(Context(0)))
+}
+
+func Test_diff_DumpWith_yamlScalars(t *testing.T) {
+	yamlA := mustLoad(t, `
+float: 1.5
+small: 1e-9
+port: "8080"
+enabled: true
+"a: b": plain
+script: |
+  echo foo
+list:
+- foo bar
+- "#comment"
+`)
+	yamlB := mustLoad(t, `
+float: 2.5
+small: 1e-9
+port: "8080"
+enabled: true
+"a: b": plain
+script: |
+  echo foo
+list:
+- foo bar
+- "#comment"
+`)
+
+	diffs := Do(yamlA, yamlB)
+	assert.Len(t, diffs, 1)
+
+	want := `
+- float: 1.5
++ float: 2.5
+  small: 1e-9
+  port: "8080"
+  enabled: true
+  "a: b": plain
+  script: |
+    echo foo
+  list:
+    - foo bar
+    - "#comment"
+`
+	assert.Equal(t, strings.TrimPrefix(want, "\n"), diffs[0].DumpWith(YAMLScalars()))
+}
+
+func Test_diff_DumpWith_yamlScalars_emptyCollections(t *testing.T) {
+	yamlA := mustLoad(t, "name: a\nsame: {}\nlist: [[]]\n")
+	yamlB := mustLoad(t, "name: a\nsame: {}\nlist: [[]]\nmap: {}\nseq: []\nitems: [{}, []]\n")
+
+	diffs := Do(yamlA, yamlB)
+	assert.Len(t, diffs, 1)
+
+	want := `
+  name: a
+  same: {}
+  list:
+    - []
++ map: {}
++ seq: []
++ items:
++   - {}
++   - []
+`
+	assert.Equal(t, strings.TrimPrefix(want, "\n"), diffs[0].DumpWith(YAMLScalars()))
+}
+
+func Test_diff_DumpWith_yamlScalars_emptyKeyAndWhitespace(t *testing.T) {
+	yamlA := mustLoad(t, "\"\": 1\ntab: \"\\ttab\"\nspace: \" space \"\n")
+	yamlB := mustLoad(t, "\"\": 2\ntab: \"\\ttab\"\nspace: \" space \"\n")
+
+	diffs := Do(yamlA, yamlB)
+	assert.Len(t, diffs, 1)
+
+	want := `
+- "": 1
++ "": 2
+  tab: "\ttab"
+  space: " space "
+`
+	assert.Equal(t, strings.TrimPrefix(want, "\n"), diffs[0].DumpWith(YAMLScalars()))
+	assert.Contains(t, diffs[0].Dump(), "- \"\": 1\n")
+}
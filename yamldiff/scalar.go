@@ -0,0 +1,140 @@
+package yamldiff
+
+import (
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// sameNumber compares numbers by value across int/float types, with the float tolerance if it's specified.
+func (r *runner) sameNumber(rawA rawType, rawB rawType) bool {
+	if !r.option.numericEquivalence {
+		return false
+	}
+
+	a, okA := toFloat(rawA)
+	b, okB := toFloat(rawB)
+	if !okA || !okB {
+		return false
+	}
+
+	if equalNumber(rawA, rawB) {
+		return true
+	}
+
+	d := math.Abs(a - b)
+	if r.option.absoluteTolerance > 0 && d <= r.option.absoluteTolerance {
+		return true
+	}
+
+	return r.option.relativeTolerance > 0 && d <= r.option.relativeTolerance*math.Max(math.Abs(a), math.Abs(b))
+}
+
+// equalNumber compares numbers by value across int/float types.
+func equalNumber(rawA rawType, rawB rawType) bool {
+	a, okA := toFloat(rawA)
+	b, okB := toFloat(rawB)
+	if !okA || !okB {
+		return false
+	}
+
+	// compare integers exactly because large ones can't be represented in float
+	intA, isIntA := toInteger(rawA)
+	intB, isIntB := toInteger(rawB)
+	if isIntA && isIntB {
+		return intA == intB
+	}
+
+	return a == b
+}
+
+// sameCanonical compares a string with a number or bool by decoding the string as a YAML scalar,
+// e.g. "8080" and 8080, or "1.0" and 1.0.
+func sameCanonical(rawA rawType, rawB rawType) bool {
+	typeA, typeB := valueType(rawA), valueType(rawB)
+	if typeA == typeB || !isCoercible(typeA) || !isCoercible(typeB) {
+		return false
+	}
+
+	s, ok := rawA.(string)
+	other := rawB
+	if !ok {
+		s, ok = rawB.(string)
+		other = rawA
+	}
+	if !ok {
+		return false
+	}
+
+	v, ok := decodeScalar(s)
+	if !ok {
+		return false
+	}
+
+	if b, ok := other.(bool); ok {
+		return v == b
+	}
+
+	return equalNumber(v, other)
+}
+
+// decodeScalar decodes the string as a plain YAML scalar, and reports whether it's a number or bool.
+// Strings which would be decoded with a comment or surrounding spaces are not scalars of the value.
+func decodeScalar(s string) (rawType, bool) {
+	if s == "" || strings.TrimSpace(s) != s || strings.ContainsAny(s, "#\n") {
+		return nil, false
+	}
+
+	var v rawType
+	if err := yaml.Unmarshal([]byte(s), &v); err != nil {
+		return nil, false
+	}
+
+	t := valueType(v)
+
+	return v, t == valueTypeNumber || t == valueTypeBool
+}
+
+func isCoercible(t valueTypeKind) bool {
+	return t == valueTypeBool || t == valueTypeNumber || t == valueTypeString
+}
+
+type integer struct {
+	negative bool
+	abs      uint64
+}
+
+func toInteger(v rawType) (integer, bool) {
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := rv.Int()
+		if i < 0 {
+			return integer{negative: true, abs: uint64(-(i + 1)) + 1}, true
+		}
+
+		return integer{abs: uint64(i)}, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return integer{abs: rv.Uint()}, true
+	}
+
+	return integer{}, false
+}
+
+func toFloat(v rawType) (float64, bool) {
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+
+	return 0, false
+}
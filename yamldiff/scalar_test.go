@@ -0,0 +1,172 @@
+package yamldiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_runner_sameNumber(t *testing.T) {
+	tests := map[string]struct {
+		opts []DoOptionFunc
+		a    rawType
+		b    rawType
+		want bool
+	}{
+		"disabled": {
+			a:    uint64(1),
+			b:    float64(1),
+			want: false,
+		},
+		"int and float": {
+			opts: []DoOptionFunc{NumericEquivalence()},
+			a:    uint64(1),
+			b:    float64(1),
+			want: true,
+		},
+		"int and uint": {
+			opts: []DoOptionFunc{NumericEquivalence()},
+			a:    int64(1),
+			b:    uint64(1),
+			want: true,
+		},
+		"negative": {
+			opts: []DoOptionFunc{NumericEquivalence()},
+			a:    int64(-1),
+			b:    uint64(1),
+			want: false,
+		},
+		"large ints": {
+			opts: []DoOptionFunc{NumericEquivalence()},
+			a:    uint64(1<<63 + 1),
+			b:    uint64(1 << 63),
+			want: false,
+		},
+		"float without tolerance": {
+			opts: []DoOptionFunc{NumericEquivalence()},
+			a:    0.30000000000000004,
+			b:    0.3,
+			want: false,
+		},
+		"relative tolerance": {
+			opts: []DoOptionFunc{FloatTolerance(0, 1e-9)},
+			a:    0.30000000000000004,
+			b:    0.3,
+			want: true,
+		},
+		"absolute tolerance": {
+			opts: []DoOptionFunc{FloatTolerance(0.01, 0)},
+			a:    1.005,
+			b:    uint64(1),
+			want: true,
+		},
+		"out of tolerance": {
+			opts: []DoOptionFunc{FloatTolerance(0.01, 0.001)},
+			a:    1.1,
+			b:    uint64(1),
+			want: false,
+		},
+		"not number": {
+			opts: []DoOptionFunc{FloatTolerance(1, 1)},
+			a:    "1",
+			b:    uint64(1),
+			want: false,
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			opts := &doOptions{}
+			for _, o := range tc.opts {
+				o(opts)
+			}
+
+			r := &runner{option: *opts}
+			assert.Equal(t, tc.want, r.sameNumber(tc.a, tc.b))
+
+			want := DiffStatusDiff
+			if tc.want {
+				want = DiffStatusSame
+			}
+			assert.Equal(t, want, r.performDiff(tc.a, tc.b, 0).status)
+		})
+	}
+}
+
+func Test_sameCanonical(t *testing.T) {
+	tests := map[string]struct {
+		a    rawType
+		b    rawType
+		want bool
+	}{
+		"string and int": {
+			a:    "8080",
+			b:    uint64(8080),
+			want: true,
+		},
+		"string and bool": {
+			a:    "true",
+			b:    true,
+			want: true,
+		},
+		"string and float": {
+			a:    "1.5",
+			b:    1.5,
+			want: true,
+		},
+		"string and float with trailing zeros": {
+			a:    "1.50",
+			b:    1.5,
+			want: true,
+		},
+		"string and integral float": {
+			a:    "1.0",
+			b:    1.0,
+			want: true,
+		},
+		"float and string": {
+			a:    1.0,
+			b:    "1.0",
+			want: true,
+		},
+		"string with comment": {
+			a:    "8080 # port",
+			b:    uint64(8080),
+			want: false,
+		},
+		"quoted string": {
+			a:    `"8080"`,
+			b:    uint64(8080),
+			want: false,
+		},
+		"number and bool": {
+			a:    uint64(1),
+			b:    true,
+			want: false,
+		},
+		"different value": {
+			a:    "8081",
+			b:    uint64(8080),
+			want: false,
+		},
+		"same type": {
+			a:    uint64(1),
+			b:    uint64(2),
+			want: false,
+		},
+		"null": {
+			a:    "<nil>",
+			b:    nil,
+			want: false,
+		},
+		"map": {
+			a:    "[]",
+			b:    rawTypeArray{},
+			want: false,
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			assert.Equal(t, tc.want, sameCanonical(tc.a, tc.b))
+		})
+	}
+}
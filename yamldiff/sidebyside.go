@@ -0,0 +1,160 @@
+package yamldiff
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	defaultSideBySideWidth = 160
+
+	sideBySideGutter = 3 // e.g. ` | `
+)
+
+// SideBySide prints A on the left and B on the right in the total width, e.g. `SideBySide(160)`.
+// The gutter tells the change, `|` for modified, `<` for removed, `>` for added and `~` for moved lines.
+// Lines longer than the column are truncated. The default width is used if the width is not positive.
+func SideBySide(width int) DumpOptionFunc {
+	return func(o *dumpOptions) {
+		o.sideBySide = true
+		o.width = width
+		if o.width <= 0 {
+			o.width = defaultSideBySideWidth
+		}
+	}
+}
+
+// printedLine is a line collected for side-by-side output.
+type printedLine struct {
+	diffPrefix string
+	text       string
+}
+
+// writeSideBySide lays out the collected lines, the removed and added lines in a row are paired.
+func (p *printer) writeSideBySide(w io.Writer) {
+	column := max((p.option.width-sideBySideGutter)/2, 1)
+
+	for i := 0; i < len(p.lines); {
+		l := p.lines[i]
+		if l.diffPrefix != "-" && l.diffPrefix != "+" {
+			gutter := " "
+			if l.diffPrefix == "~" {
+				gutter = "~"
+			}
+
+			p.writeRow(w, column, l, gutter, l)
+			i++
+
+			continue
+		}
+
+		removed, added := []*printedLine{}, []*printedLine{}
+		for ; i < len(p.lines) && (p.lines[i].diffPrefix == "-" || p.lines[i].diffPrefix == "+"); i++ {
+			if p.lines[i].diffPrefix == "-" {
+				removed = append(removed, p.lines[i])
+			} else {
+				added = append(added, p.lines[i])
+			}
+		}
+
+		for n := 0; n < max(len(removed), len(added)); n++ {
+			switch {
+			case n >= len(removed):
+				p.writeRow(w, column, nil, ">", added[n])
+			case n >= len(added):
+				p.writeRow(w, column, removed[n], "<", nil)
+			default:
+				p.writeRow(w, column, removed[n], "|", added[n])
+			}
+		}
+	}
+}
+
+func (p *printer) writeRow(w io.Writer, column int, left *printedLine, gutter string, right *printedLine) {
+	var b strings.Builder
+
+	cell := p.cell(left, column)
+	b.WriteString(cell)
+	b.WriteString(strings.Repeat(" ", column-visibleWidth(cell)))
+	b.WriteString(" " + gutter)
+	if right != nil {
+		b.WriteString(" " + p.cell(right, column))
+	}
+	b.WriteString("\n")
+
+	_, _ = io.WriteString(w, b.String())
+}
+
+func (p *printer) cell(l *printedLine, column int) string {
+	if l == nil {
+		return ""
+	}
+
+	text, truncated := truncate(l.text, column)
+	switch {
+	case p.option.color:
+		return lineColor(l.diffPrefix) + text + ansiReset
+	case truncated && strings.Contains(text, "\x1b["):
+		return text + ansiReset
+	}
+
+	return text
+}
+
+// truncate cuts the string to the width, ANSI escape sequences are kept and not counted.
+func truncate(s string, width int) (string, bool) {
+	var b strings.Builder
+
+	n := 0
+	for i := 0; i < len(s); {
+		if end := escapeEnd(s, i); end > i {
+			b.WriteString(s[i:end])
+			i = end
+
+			continue
+		}
+
+		if n == width {
+			return b.String(), true
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		b.WriteRune(r)
+		i += size
+		n++
+	}
+
+	return b.String(), false
+}
+
+func visibleWidth(s string) int {
+	n := 0
+	for i := 0; i < len(s); {
+		if end := escapeEnd(s, i); end > i {
+			i = end
+
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		n++
+	}
+
+	return n
+}
+
+// escapeEnd returns the end of ANSI escape sequence like `\x1b[31m` at i, or i if it's not.
+func escapeEnd(s string, i int) int {
+	if !strings.HasPrefix(s[i:], "\x1b[") {
+		return i
+	}
+
+	end := strings.IndexByte(s[i:], 'm')
+	if end < 0 {
+		return i
+	}
+
+	return i + end + 1
+}
@@ -0,0 +1,82 @@
+package yamldiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_diff_DumpWith_sideBySide(t *testing.T) {
+	yamlA := mustLoad(t, `
+name: app
+image: my-app:1.0.0
+removed: foo
+list:
+- a
+- b
+`)
+	yamlB := mustLoad(t, `
+name: app
+image: my-app:1.1.0
+list:
+- b
+- a
+added: a-very-long-value-which-is-truncated
+`)
+
+	diffs := Do(yamlA, yamlB)
+	assert.Len(t, diffs, 1)
+
+	want := `
+name: "app"          name: "app"
+image: "my-app:1.0 | image: "my-app:1.1
+removed: "foo"     <
+list:                list:
+  # moved from [0] ~   # moved from [0]
+  - "a"            ~   - "a"
+  - "b"                - "b"
+                   > added: "a-very-lon
+`
+	assert.Equal(t, strings.TrimPrefix(want, "\n"), diffs[0].DumpWith(SideBySide(39)))
+}
+
+func Test_truncate(t *testing.T) {
+	tests := map[string]struct {
+		s         string
+		width     int
+		want      string
+		truncated bool
+	}{
+		"short": {
+			s:     "abc",
+			width: 5,
+			want:  "abc",
+		},
+		"long": {
+			s:         "abcdef",
+			width:     3,
+			want:      "abc",
+			truncated: true,
+		},
+		"multibyte": {
+			s:         "あいうえお",
+			width:     2,
+			want:      "あい",
+			truncated: true,
+		},
+		"escape sequences": {
+			s:         "a\x1b[7mbc\x1b[27md",
+			width:     2,
+			want:      "a\x1b[7mb",
+			truncated: true,
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			got, truncated := truncate(tc.s, tc.width)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.truncated, truncated)
+		})
+	}
+}
@@ -0,0 +1,399 @@
+package yamldiff
+
+import (
+	"strings"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+// OriginalSource prints values with the original source text, so comments, quoting style, anchors and flow style are kept.
+// It works for the documents loaded by Load, and falls back to the normal output for the others.
+// Map fields and array elements are printed as the source node including the comments, and changed maps or arrays are printed per child.
+// Changed flow style maps or arrays are printed as a whole, because a line of the source has all children.
+// Unchanged fields merged by `<<` are printed as the `<<` field.
+func OriginalSource() DumpOptionFunc {
+	return func(o *dumpOptions) {
+		o.originalSource = true
+	}
+}
+
+// parseSource parses the document keeping comments, nil if it can't be parsed.
+func parseSource(s string) ast.Node {
+	f, err := parser.ParseBytes([]byte(s), parser.ParseComments)
+	if err != nil || len(f.Docs) == 0 {
+		return nil
+	}
+
+	return f.Docs[0].Body
+}
+
+// selectSource returns the source node of the value at the path, nil if it's not found.
+func selectSource(root ast.Node, path Path) ast.Node {
+	n, ok := newSourceIndex(root).nodes[path.String()]
+	if !ok {
+		return nil
+	}
+
+	if v, ok := n.(*ast.MappingValueNode); ok {
+		return v.Value
+	}
+
+	return n
+}
+
+// sourceIndex maps Path.String to the node, a map field is *ast.MappingValueNode and an array element is the value.
+// The comments of array elements are kept in items because the value node doesn't have them.
+// The fields merged by `<<` don't have their own node, so merges maps them to the `<<` field.
+type sourceIndex struct {
+	nodes   map[string]ast.Node
+	items   map[string]*sourceItem
+	merges  map[string]*ast.MappingValueNode
+	anchors map[string]ast.Node
+}
+
+// sourceItem is the comments of an array element, the head comment lines above `-` and the line comment after `-`.
+type sourceItem struct {
+	head []string
+	line string
+}
+
+func newSourceIndex(root ast.Node) sourceIndex {
+	idx := sourceIndex{
+		nodes:   map[string]ast.Node{},
+		items:   map[string]*sourceItem{},
+		merges:  map[string]*ast.MappingValueNode{},
+		anchors: map[string]ast.Node{},
+	}
+	if root == nil {
+		return idx
+	}
+
+	idx.nodes[""] = root
+	idx.add(root, Path{})
+
+	return idx
+}
+
+func (idx sourceIndex) add(n ast.Node, path Path) {
+	idx.addAnchor(n)
+
+	switch t := unwrapSource(n).(type) {
+	case *ast.MappingNode:
+		idx.addMappingValues(t.Values, path)
+	case *ast.MappingValueNode:
+		idx.addMappingValues([]*ast.MappingValueNode{t}, path)
+	case *ast.SequenceNode:
+		for i, v := range t.Values {
+			idx.nodes[path.appendIndex(i).String()] = v
+			if item := newSourceItem(t, i); item != nil {
+				idx.items[path.appendIndex(i).String()] = item
+			}
+			idx.add(v, path.appendIndex(i))
+		}
+	}
+}
+
+// addAnchor keeps the anchored node, so that the aliases in merge keys are resolved.
+func (idx sourceIndex) addAnchor(n ast.Node) {
+	for {
+		switch t := n.(type) {
+		case *ast.AnchorNode:
+			idx.anchors[t.Name.GetToken().Value] = t.Value
+			n = t.Value
+		case *ast.TagNode:
+			n = t.Value
+		default:
+			return
+		}
+	}
+}
+
+// addMappingValues adds the fields, then the fields merged by `<<` which are not overridden.
+func (idx sourceIndex) addMappingValues(values []*ast.MappingValueNode, path Path) {
+	merges := []*ast.MappingValueNode{}
+	for _, v := range values {
+		if _, ok := v.Key.(*ast.MergeKeyNode); ok {
+			merges = append(merges, v)
+		}
+
+		key := sourceKey(v)
+		idx.nodes[path.appendKey(key).String()] = v
+		idx.add(v.Value, path.appendKey(key))
+	}
+
+	for _, m := range merges {
+		merged := []ast.Node{m.Value}
+		if seq, ok := unwrapSource(m.Value).(*ast.SequenceNode); ok {
+			merged = seq.Values
+		}
+
+		for _, n := range merged {
+			for _, v := range idx.mappingValues(n) {
+				p := path.appendKey(sourceKey(v)).String()
+				if _, ok := idx.nodes[p]; ok {
+					continue
+				}
+				if _, ok := idx.merges[p]; !ok {
+					idx.merges[p] = m
+				}
+			}
+		}
+	}
+}
+
+// mappingValues returns the fields of the map, the alias is resolved.
+func (idx sourceIndex) mappingValues(n ast.Node) []*ast.MappingValueNode {
+	if a, ok := unwrapSource(n).(*ast.AliasNode); ok {
+		n = idx.anchors[a.Value.GetToken().Value]
+	}
+
+	switch t := unwrapSource(n).(type) {
+	case *ast.MappingNode:
+		return t.Values
+	case *ast.MappingValueNode:
+		return []*ast.MappingValueNode{t}
+	}
+
+	return nil
+}
+
+func sourceKey(v *ast.MappingValueNode) string {
+	if s, ok := unwrapSource(v.Key).(*ast.StringNode); ok {
+		return s.Value
+	}
+
+	return v.Key.String()
+}
+
+// newSourceItem returns the comments of the i-th element of the block sequence, nil if there are no comments.
+func newSourceItem(seq *ast.SequenceNode, i int) *sourceItem {
+	if seq.IsFlowStyle || len(seq.Entries) <= i {
+		return nil
+	}
+
+	e := seq.Entries[i]
+	item := &sourceItem{}
+	if e.LineComment != nil {
+		item.line = strings.TrimSpace(e.LineComment.String())
+	}
+
+	head := e.HeadComment
+	if i == 0 && head == nil && e.LineComment == nil {
+		// the head comment of the first element is parsed as the comment of the sequence
+		head = seq.GetComment()
+	}
+	if head != nil {
+		for _, l := range strings.Split(head.String(), "\n") {
+			item.head = append(item.head, strings.TrimSpace(l))
+		}
+	}
+
+	if item.line == "" && len(item.head) == 0 {
+		return nil
+	}
+
+	return item
+}
+
+// dash returns `-` of the array element with the line comment.
+func (item *sourceItem) dash() string {
+	if item == nil || item.line == "" {
+		return "-"
+	}
+
+	return "- " + item.line
+}
+
+// unwrapSource returns the node which has the value, anchors and tags are removed.
+func unwrapSource(n ast.Node) ast.Node {
+	for {
+		switch t := n.(type) {
+		case *ast.AnchorNode:
+			n = t.Value
+		case *ast.TagNode:
+			n = t.Value
+		case *ast.MappingKeyNode:
+			n = t.Value
+		default:
+			return n
+		}
+	}
+}
+
+// sourceLines returns the source text of the node without the common indent.
+func sourceLines(n ast.Node) []string {
+	lines := strings.Split(strings.TrimRight(n.String(), "\n"), "\n")
+
+	common := -1
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+
+		if width := len(l) - len(strings.TrimLeft(l, " ")); common < 0 || width < common {
+			common = width
+		}
+	}
+
+	for i, l := range lines {
+		if len(l) >= common && common > 0 {
+			lines[i] = l[common:]
+		}
+	}
+
+	return lines
+}
+
+// source returns the source node at the current path of the side of the prefix.
+func (p *printer) source(diffPrefix string) (ast.Node, bool) {
+	if !p.option.originalSource {
+		return nil, false
+	}
+
+	idx, path := p.sourceA, p.pathA
+	if diffPrefix == "+" || diffPrefix == "~" {
+		idx, path = p.sourceB, p.pathB
+	}
+
+	n, ok := idx.nodes[path.String()]
+
+	return n, ok && n != nil
+}
+
+// sourceItem returns the comments of the array element at the current path of the side of the prefix, nil if there are no comments.
+func (p *printer) sourceItem(diffPrefix string) *sourceItem {
+	if !p.option.originalSource {
+		return nil
+	}
+
+	idx, path := p.sourceA, p.pathA
+	if diffPrefix == "+" || diffPrefix == "~" {
+		idx, path = p.sourceB, p.pathB
+	}
+
+	return idx.items[path.String()]
+}
+
+// dumpSourceItemHead prints the head comments of the array element at the current path, and returns `-` with the line comment.
+func (p *printer) dumpSourceItemHead(diffPrefix string, level int) string {
+	item := p.sourceItem(diffPrefix)
+	if item != nil {
+		for _, h := range item.head {
+			p.line(diffPrefix, level, "", h)
+		}
+	}
+
+	return item.dash()
+}
+
+// isFlowSource checks the value at the current path is a flow style map or array in the source of either side.
+func (p *printer) isFlowSource() bool {
+	for _, diffPrefix := range []string{"-", "+"} {
+		n, ok := p.source(diffPrefix)
+		if !ok {
+			continue
+		}
+
+		if v, ok := n.(*ast.MappingValueNode); ok {
+			n = v.Value
+		}
+
+		switch t := unwrapSource(n).(type) {
+		case *ast.MappingNode:
+			if t.IsFlowStyle {
+				return true
+			}
+		case *ast.SequenceNode:
+			if t.IsFlowStyle {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// dumpSource prints the value at the current path with the source text, false if there is no source.
+func (p *printer) dumpSource(diffPrefix string, level int, arrayItem bool) bool {
+	n, ok := p.source(diffPrefix)
+	if !ok {
+		return false
+	}
+
+	lines := sourceLines(n)
+
+	if arrayItem {
+		dash := p.dumpSourceItemHead(diffPrefix, level)
+
+		// block maps and arrays, and the values after the line comment start at the next line of `-`
+		if dash != "-" || isBlockSource(n, lines[0]) {
+			p.line(diffPrefix, level, "", dash)
+			level++
+		} else {
+			lines[0] = "- " + lines[0]
+		}
+	}
+
+	p.line(diffPrefix, level, "", lines[0])
+	for _, l := range lines[1:] {
+		p.line(diffPrefix, level, "", l)
+	}
+
+	return true
+}
+
+// isBlockSource checks the node is a block style map or array, the first line tells flow style.
+func isBlockSource(n ast.Node, first string) bool {
+	switch unwrapSource(n).(type) {
+	case *ast.MappingNode, *ast.MappingValueNode, *ast.SequenceNode:
+		first = strings.TrimSpace(first)
+
+		return !strings.HasPrefix(first, "[") && !strings.HasPrefix(first, "{")
+	}
+
+	return false
+}
+
+// dumpSourceMerge prints the `<<` field which merges the field at the current path, only once for the printed map.
+// It's false if the field is not merged in either side.
+func (p *printer) dumpSourceMerge(level int, printed map[ast.Node]bool) bool {
+	if !p.option.originalSource {
+		return false
+	}
+
+	m, ok := p.sourceA.merges[p.pathA.String()]
+	if _, okB := p.sourceB.merges[p.pathB.String()]; !ok || !okB {
+		return false
+	}
+
+	if !printed[m] {
+		printed[m] = true
+		for _, l := range sourceLines(m) {
+			p.line(" ", level, "", l)
+		}
+	}
+
+	return true
+}
+
+// dumpSourceHead prints the comments and the key line of the map field at the current path, false if there is no source.
+func (p *printer) dumpSourceHead(level int) bool {
+	n, ok := p.source(" ")
+	if !ok {
+		return false
+	}
+
+	if _, ok := n.(*ast.MappingValueNode); !ok {
+		return false
+	}
+
+	for _, l := range sourceLines(n) {
+		p.line(" ", level, "", l)
+		if !strings.HasPrefix(strings.TrimSpace(l), "#") {
+			break
+		}
+	}
+
+	return true
+}
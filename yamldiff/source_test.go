@@ -0,0 +1,240 @@
+package yamldiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYamlDiff_DumpWith_originalSource(t *testing.T) {
+	yamlA := mustLoad(t, `
+# service config
+base: &base
+  a: 'single' # trailing
+  b: "double"
+flow: {x: 1, y: [1, 2]}
+list:
+  - name: foo
+    value: 0x10
+port: 80
+`)
+	yamlB := mustLoad(t, `
+# service config
+base: &base
+  a: 'single' # trailing
+  b: "changed"
+flow: {x: 1, y: [1, 2]}
+list:
+  - name: foo
+    value: 0x20
+port: 8080 # new port
+`)
+
+	diffs := Do(yamlA, yamlB)
+	assert.Len(t, diffs, 1)
+
+	want := `
+  # service config
+  base: &base
+    a: 'single' # trailing
+-   b: "double"
++   b: "changed"
+  flow: {x: 1, y: [1, 2]}
+  list:
+    -
+      name: foo
+-     value: 0x10
++     value: 0x20
+- port: 80
++ port: 8080 # new port
+`
+	assert.Equal(t, strings.TrimPrefix(want, "\n"), diffs[0].DumpWith(OriginalSource()))
+}
+
+func TestYamlDiff_DumpWith_originalSource_context(t *testing.T) {
+	yamlA := mustLoad(t, `
+# spec
+spec: # the spec
+  a: 1
+  b: 2
+list:
+  # first
+  - name: a
+  - {name: b}
+  - 'c'
+x: 1
+y: 'single' # quoted
+`)
+	yamlB := mustLoad(t, `
+# spec
+spec: # the spec
+  a: 1
+  b: 2
+list:
+  # first
+  - name: a
+  - {name: b}
+  - 'd'
+x: 2
+y: 'single' # quoted
+`)
+
+	diffs := Do(yamlA, yamlB)
+	assert.Len(t, diffs, 1)
+
+	want := `
+  # spec
+  spec: # the spec
+    ... (2 unchanged fields)
+  list:
+    # first
+    -
+      ... (1 unchanged field)
+    - {name: b}
+-   - 'c'
++   - 'd'
+- x: 1
++ x: 2
+  y: 'single' # quoted
+`
+	assert.Equal(t, strings.TrimPrefix(want, "\n"), diffs[0].DumpWith(OriginalSource(), Context(2)))
+}
+
+func TestYamlDiff_DumpWith_originalSource_mergeKeys(t *testing.T) {
+	yamlA := mustLoad(t, `
+a: &a {x: 1, y: 1}
+b: &b {z: 1}
+obj:
+  <<: *a # defaults
+  v: 1
+list:
+  - <<: [*a, *b]
+    w: 2
+    v: 1
+`)
+	yamlB := mustLoad(t, `
+a: &a {x: 1, y: 1}
+b: &b {z: 1}
+obj:
+  <<: *a # defaults
+  v: 2
+list:
+  - <<: [*a, *b]
+    w: 2
+    v: 2
+`)
+
+	diffs := Do(yamlA, yamlB)
+	assert.Len(t, diffs, 1)
+
+	want := `
+  a: &a {x: 1, y: 1}
+  b: &b {z: 1}
+  obj:
+    <<: *a # defaults
+-   v: 1
++   v: 2
+  list:
+    -
+      <<: [*a, *b]
+      w: 2
+-     v: 1
++     v: 2
+`
+	assert.Equal(t, strings.TrimPrefix(want, "\n"), diffs[0].DumpWith(OriginalSource()))
+}
+
+func TestYamlDiff_DumpWith_originalSource_missingDocument(t *testing.T) {
+	diffs := Do(mustLoad(t, "a: 1"), mustLoad(t, "a: 1\n---\n# added\nlist: [1, 2]"))
+	assert.Len(t, diffs, 2)
+
+	assert.Equal(t, "+ # added\n+ list: [1, 2]\n", diffs[1].DumpWith(OriginalSource()))
+}
+
+func Test_diff_DumpWith_originalSource_withoutSource(t *testing.T) {
+	d := &diff{a: "foo", b: "bar", status: DiffStatusDiff}
+
+	assert.Equal(t, d.Dump(), d.DumpWith(OriginalSource()))
+}
+
+func TestYamlDiff_DumpWith_originalSource_flow(t *testing.T) {
+	diffs := Do(mustLoad(t, "flow: [1, 2, 3]\nm: {a: 1, b: 2}\n"), mustLoad(t, "flow: [1, 2, 4]\nm: {a: 1, b: 3}\n"))
+	assert.Len(t, diffs, 1)
+
+	want := `
+- flow: [1, 2, 3]
++ flow: [1, 2, 4]
+- m: {a: 1, b: 2}
++ m: {a: 1, b: 3}
+`
+	assert.Equal(t, strings.TrimPrefix(want, "\n"), diffs[0].DumpWith(OriginalSource()))
+
+	sideBySide := diffs[0].DumpWith(OriginalSource(), SideBySide(60))
+	assert.Contains(t, sideBySide, "flow: [1, 2, 3]")
+	assert.Contains(t, sideBySide, "flow: [1, 2, 4]")
+}
+
+func TestYamlDiff_DumpWith_originalSource_arrayItems(t *testing.T) {
+	yamlA := mustLoad(t, `
+list:
+  # first item
+  - x
+  - name: a # trailing
+    value: 1
+  - [1, 2]
+items:
+  - # first map
+    name: b
+    value: 1
+  # second map
+  - name: c
+order:
+  - 'a'
+  - 'b'
+`)
+	yamlB := mustLoad(t, `
+list:
+  # first item
+  - x
+  - name: a # trailing
+    value: 2
+  - [1, 2]
+items:
+  - # first map
+    name: b
+    value: 2
+  # second map
+  - name: c
+order:
+  - 'b'
+  - 'a'
+`)
+
+	diffs := Do(yamlA, yamlB)
+	assert.Len(t, diffs, 1)
+
+	want := `
+  list:
+    # first item
+    - x
+    - [1, 2]
+    -
+      name: a # trailing
+-     value: 1
++     value: 2
+  items:
+    # second map
+    -
+      name: c
+    - # first map
+      name: b
+-     value: 1
++     value: 2
+  order:
+~   # moved from [0] to [1]
+~   - 'a'
+    - 'b'
+`
+	assert.Equal(t, strings.TrimPrefix(want, "\n"), diffs[0].DumpWith(OriginalSource()))
+}
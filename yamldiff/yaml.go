@@ -2,23 +2,39 @@ package yamldiff
 
 import (
 	"crypto/rand"
+	"errors"
 	"fmt"
 	"math"
 	"math/big"
-	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
 )
 
+var ErrInvalidSelectPath = errors.New("yamldiff: invalid select path")
+
 type RawYaml struct {
 	raw interface{}
 	id  string
+
+	// parsed document keeping comments and styles, nil if it's not loaded from text
+	source ast.Node
 }
 
 type RawYamlList []*RawYaml
 
+func (l RawYamlList) raws() rawTypeArray {
+	raws := make(rawTypeArray, 0, len(l))
+	for _, y := range l {
+		raws = append(raws, y.raw)
+	}
+
+	return raws
+}
+
 type Diffs []*diff
 
 func newRawYaml(raw interface{}) *RawYaml {
@@ -44,10 +60,46 @@ func Load(s string) (RawYamlList, error) {
 			return nil, fmt.Errorf("yamldiff: failed to unmarshal yaml: %w", err)
 		}
 
-		results = append(
-			results,
-			newRawYaml(out),
-		)
+		raw := newRawYaml(out)
+		raw.source = parseSource(y)
+
+		results = append(results, raw)
+	}
+
+	return results, nil
+}
+
+// Select extracts the value at the path from each document, e.g. `Select(yamls, ".spec.template")`.
+// The document which doesn't have the path becomes null, so the positions of documents are kept.
+// It returns ErrInvalidSelectPath if the path has wildcards or none of the documents has the path.
+// The source of the selected value is kept for OriginalSource.
+func Select(yamls RawYamlList, path string) (RawYamlList, error) {
+	p := parsePathPattern(path)
+
+	target, ok := p.path()
+	if !ok {
+		return nil, fmt.Errorf("%w: wildcards are not supported: %s", ErrInvalidSelectPath, path)
+	}
+
+	found := false
+	results := make(RawYamlList, 0, len(yamls))
+	for _, y := range yamls {
+		v, ok := lookup(y.raw, p)
+		if !ok {
+			results = append(results, newRawYaml(nil))
+
+			continue
+		}
+
+		found = true
+
+		raw := newRawYaml(v)
+		raw.source = selectSource(y.source, target)
+		results = append(results, raw)
+	}
+
+	if !found && len(yamls) > 0 {
+		return nil, fmt.Errorf("%w: not found in any document: %s", ErrInvalidSelectPath, path)
 	}
 
 	return results, nil
@@ -57,9 +109,25 @@ type YamlDiff struct {
 	d   *diff
 	idA string
 	idB string
+
+	// position of document in A or B, -1 if it's missing
+	indexA int
+	indexB int
+
+	// paired by document key
+	identified bool
+
+	sourceA ast.Node
+	sourceB ast.Node
 }
 
+// Status returns the status of the document.
+// It's DiffStatusMoved if the document is the same except for the positions of array elements.
 func (y *YamlDiff) Status() DiffStatus {
+	if y.d.status == DiffStatusSame && y.d.hasMoved {
+		return DiffStatusMoved
+	}
+
 	return y.d.status
 }
 
@@ -67,9 +135,30 @@ func (y *YamlDiff) Dump() string {
 	return y.d.Dump()
 }
 
+func (y *YamlDiff) DumpWith(options ...DumpOptionFunc) string {
+	return y.d.dumpWith(y.sourceA, y.sourceB, options)
+}
+
 type doOptions struct {
-	emptyAsNull bool
-	zeroAsNull  bool
+	emptyAsNull    bool
+	zeroAsNull     bool
+	arrayMatchKeys []*arrayMatchKey
+	orderedArrays  bool
+	documentKey    DocumentKeyFunc
+	ignorePaths    []pathPattern
+
+	similarityThreshold float64
+
+	numericEquivalence bool
+	coerceTypes        bool
+	reportCoercedTypes bool
+	absoluteTolerance  float64
+	relativeTolerance  float64
+}
+
+type arrayMatchKey struct {
+	path pathPattern
+	key  string
 }
 
 type DoOptionFunc func(o *doOptions)
@@ -86,6 +175,120 @@ func ZeroAsNull() DoOptionFunc {
 	}
 }
 
+// NumericEquivalence compares numbers by value across int/float types, e.g. `1` and `1.0` are the same.
+func NumericEquivalence() DoOptionFunc {
+	return func(o *doOptions) {
+		o.numericEquivalence = true
+	}
+}
+
+// FloatTolerance treats numbers as the same if the difference is within the absolute tolerance
+// or the relative tolerance of the larger absolute value, e.g. `FloatTolerance(0, 1e-9)`.
+// It implies NumericEquivalence.
+func FloatTolerance(absolute float64, relative float64) DoOptionFunc {
+	return func(o *doOptions) {
+		o.numericEquivalence = true
+		o.absoluteTolerance = absolute
+		o.relativeTolerance = relative
+	}
+}
+
+// CoerceTypes treats a string as the same as a number or bool if the string is the value as a YAML scalar,
+// e.g. "8080" and 8080, "1.0" and 1.0, "true" and true.
+func CoerceTypes() DoOptionFunc {
+	return func(o *doOptions) {
+		o.coerceTypes = true
+	}
+}
+
+// ReportCoercedTypes reports the type change of the scalars treated as the same by CoerceTypes as ChangeKindCoerced.
+// The diff status is still the same. It implies CoerceTypes.
+func ReportCoercedTypes() DoOptionFunc {
+	return func(o *doOptions) {
+		o.coerceTypes = true
+		o.reportCoercedTypes = true
+	}
+}
+
+// MatchArrayBy pairs elements of the arrays at the path by the value of the key, e.g. `MatchArrayBy("spec.containers", "name")`.
+// The path can contain wildcards, `*` for any key and `[*]` for any index.
+func MatchArrayBy(path string, key string) DoOptionFunc {
+	return func(o *doOptions) {
+		o.arrayMatchKeys = append(o.arrayMatchKeys, &arrayMatchKey{
+			path: parsePathPattern(path),
+			key:  key,
+		})
+	}
+}
+
+// IgnorePaths excludes the values at the paths from the diff, e.g. `IgnorePaths("metadata.annotations", "**.resourceVersion")`.
+// The path can contain wildcards, `*` for any key, `[*]` for any index and `**` for any descendants.
+func IgnorePaths(paths ...string) DoOptionFunc {
+	return func(o *doOptions) {
+		for _, p := range paths {
+			o.ignorePaths = append(o.ignorePaths, parsePathPattern(p))
+		}
+	}
+}
+
+// OrderedArrays compares arrays as ordered sequences, so reordered elements are reported.
+func OrderedArrays() DoOptionFunc {
+	return func(o *doOptions) {
+		o.orderedArrays = true
+	}
+}
+
+// DocumentKeyFunc returns the identity of the document, false if the document doesn't have it.
+// The document is decoded with ordered map, so maps are yaml.MapSlice.
+type DocumentKeyFunc func(doc interface{}) (string, bool)
+
+// PairDocumentsBy pairs documents which have the same key.
+// Documents which can't be paired are reported as added or removed,
+// and documents which don't have the key are paired by similarity.
+func PairDocumentsBy(fn DocumentKeyFunc) DoOptionFunc {
+	return func(o *doOptions) {
+		o.documentKey = fn
+	}
+}
+
+// KubernetesIdentity pairs documents by kubernetes object identity, API group, kind, namespace and name.
+func KubernetesIdentity() DoOptionFunc {
+	return PairDocumentsBy(kubernetesDocumentKey)
+}
+
+// DocumentKeyByPaths returns DocumentKeyFunc which uses the values at the paths as the key, e.g. `metadata.name`.
+// The values are quoted and joined with "/", so that the values containing "/" don't collide.
+// The document doesn't have the key if any of the paths is missing.
+func DocumentKeyByPaths(paths ...string) DocumentKeyFunc {
+	patterns := make([]pathPattern, 0, len(paths))
+	for _, p := range paths {
+		patterns = append(patterns, parsePathPattern(p))
+	}
+
+	return func(doc interface{}) (string, bool) {
+		values := make([]string, 0, len(patterns))
+		for _, p := range patterns {
+			v, ok := lookup(doc, p)
+			if !ok {
+				return "", false
+			}
+
+			values = append(values, strconv.Quote(fmt.Sprint(v)))
+		}
+
+		return strings.Join(values, "/"), true
+	}
+}
+
+// SimilarityThreshold reports a pair of documents or array elements as removal and addition
+// if the similarity is below the ratio, e.g. `SimilarityThreshold(0.5)`.
+// The similarity is 1 - (diff count / total content of both sides), 0 for the completely different values and 1 for the same ones.
+func SimilarityThreshold(ratio float64) DoOptionFunc {
+	return func(o *doOptions) {
+		o.similarityThreshold = ratio
+	}
+}
+
 func Do(rawA RawYamlList, rawB RawYamlList, options ...DoOptionFunc) []*YamlDiff {
 	opts := &doOptions{}
 	for _, o := range options {
@@ -110,70 +313,263 @@ type runner struct {
 	rawA   RawYamlList
 	rawB   RawYamlList
 	diffs  []*YamlDiff
+
+	// path of the value currently compared
+	path Path
 }
 
 func (r *runner) performAllDiff() {
 	diffs := make([]*YamlDiff, 0, len(r.rawA)*len(r.rawB))
-	for _, a := range r.rawA {
-		for _, b := range r.rawB {
+	for indexA, a := range r.rawA {
+		for indexB, b := range r.rawB {
+			identified, ok := r.canPair(a, b)
+			if !ok {
+				continue
+			}
+
 			diffs = append(diffs, &YamlDiff{
-				d:   r.performDiff(a.raw, b.raw, 0),
-				idA: a.id,
-				idB: b.id,
+				d:          r.performDiff(a.raw, b.raw, 0),
+				idA:        a.id,
+				idB:        b.id,
+				indexA:     indexA,
+				indexB:     indexB,
+				identified: identified,
+				sourceA:    a.source,
+				sourceB:    b.source,
 			})
 		}
 	}
 
 	// Make more diffs `A:nil`` and `nil:B`` to find missing entry
-	for _, a := range r.rawA {
+	for indexA, a := range r.rawA {
 		diffs = append(diffs, &YamlDiff{
-			d:   r.performDiff(a.raw, nil, 0),
-			idA: a.id,
-			idB: fmt.Sprintf("empty-%d-%d", time.Now().UnixNano(), randInt()),
+			d:       r.performDiff(a.raw, nil, 0),
+			idA:     a.id,
+			idB:     fmt.Sprintf("empty-%d-%d", time.Now().UnixNano(), randInt()),
+			indexA:  indexA,
+			indexB:  -1,
+			sourceA: a.source,
 		})
 	}
 
-	for _, b := range r.rawB {
+	for indexB, b := range r.rawB {
 		diffs = append(diffs, &YamlDiff{
-			d:   r.performDiff(nil, b.raw, 0),
-			idA: fmt.Sprintf("empty-%d-%d", time.Now().UnixNano(), randInt()),
-			idB: b.id,
+			d:       r.performDiff(nil, b.raw, 0),
+			idA:     fmt.Sprintf("empty-%d-%d", time.Now().UnixNano(), randInt()),
+			idB:     b.id,
+			indexA:  -1,
+			indexB:  indexB,
+			sourceB: b.source,
 		})
 	}
 
 	r.diffs = diffs
 }
 
+// canPair checks the documents have the same key if the document key is specified, and reports whether they are identified by the key.
+// The documents which don't have key can be paired with each other.
+func (r *runner) canPair(a *RawYaml, b *RawYaml) (bool, bool) {
+	if r.option.documentKey == nil {
+		return false, true
+	}
+
+	keyA, okA := r.option.documentKey(a.raw)
+	keyB, okB := r.option.documentKey(b.raw)
+	if okA || okB {
+		same := okA && okB && keyA == keyB
+
+		return same, same
+	}
+
+	return false, true
+}
+
+// findMinimumDiffs chooses the pairs of documents with the minimum total diff count.
+// The same documents are paired first, and the rest of documents in A or B are paired with one of the other side
+// or with nothing (A:nil or nil:B), whose diff count is the cost of the missing document.
+// A pair can be chosen if it has no more diffs than either of the missing documents, and it's preferred on a tie.
+// Identified pairs are always preferred to missing documents, and they never compete with other pairs
+// because the documents which have the key can be paired only with the same key.
 func (r *runner) findMinimumDiffs() {
-	sort.Slice(r.diffs, func(i, j int) bool {
-		if r.diffs[i].d.status == DiffStatusSame {
-			return true
-		}
-		return r.diffs[i].d.diffCount < r.diffs[j].d.diffCount
-	})
+	pairs := map[[2]int]*YamlDiff{}
+	for _, d := range r.diffs {
+		pairs[[2]int{d.indexA, d.indexB}] = d
+	}
 
 	result := []*YamlDiff{}
-	checked := map[string]interface{}{}
+	foundB := map[int]struct{}{}
+	restA := []int{}
 
-	for _, d := range r.diffs {
-		if _, ok := checked[d.idA]; ok {
-			continue
+	for i := range r.rawA {
+		found := false
+		for j := range r.rawB {
+			if _, ok := foundB[j]; ok {
+				continue
+			}
+
+			if d, ok := pairs[[2]int{i, j}]; ok && d.d.status == DiffStatusSame {
+				result = append(result, d)
+				foundB[j] = struct{}{}
+				found = true
+
+				break
+			}
 		}
-		if _, ok := checked[d.idB]; ok {
-			continue
+
+		if !found {
+			restA = append(restA, i)
+		}
+	}
+
+	restB := []int{}
+	for j := range r.rawB {
+		if _, ok := foundB[j]; !ok {
+			restB = append(restB, j)
 		}
+	}
+
+	n, m := len(restA), len(restB)
 
-		result = append(result, d)
+	// the tie-breaks of positions and missing documents never exceed one unit of cost
+	scale := int64(n+m+1) * int64(n+m+1)
+	cost := func(d *YamlDiff) int64 {
+		return int64(d.d.diffCount) * scale
+	}
+
+	missingA := make([]int64, n)
+	for i, a := range restA {
+		missingA[i] = cost(pairs[[2]int{a, -1}]) + int64(n+m)
+	}
+	missingB := make([]int64, m)
+	for j, b := range restB {
+		missingB[j] = cost(pairs[[2]int{-1, b}]) + int64(n+m)
+	}
 
-		checked[d.idA] = struct{}{}
-		checked[d.idB] = struct{}{}
+	// forbidden exceeds the cost of any assignment without it, e.g. all documents are missing,
+	// and it's bounded by the size of the documents
+	forbidden := int64(n+1)*scale + 1
+	for _, c := range append(missingA, missingB...) {
+		forbidden += c
 	}
 
-	// Even if missing entries in A or B, it should be covered by A:nil or nil:B case.
+	similar := r.similarPairs(r.rawA.raws(), r.rawB.raws(), false)
+
+	// rows are A and B added, columns are B and A removed
+	cols := assign(n+m, n+m, func(i int, j int) int64 {
+		switch {
+		case i < n && j < m:
+			d, ok := pairs[[2]int{restA[i], restB[j]}]
+			if !ok {
+				return forbidden
+			}
+
+			c := cost(d)
+			if !d.identified && (c > min(missingA[i], missingB[j]) || !similar(d.d, d.indexA, d.indexB)) {
+				return forbidden
+			}
+			if d.identified {
+				c = min(c, missingA[i]+missingB[j]-scale)
+			}
+
+			return c + int64(abs(restA[i]-restB[j]))
+		case i < n:
+			if j-m != i {
+				return forbidden
+			}
+
+			return missingA[i]
+		case j < m:
+			if i-n != j {
+				return forbidden
+			}
+
+			return missingB[j]
+		}
+
+		return 0
+	})
+
+	for i, j := range cols[:n] {
+		if j < m {
+			result = append(result, pairs[[2]int{restA[i], restB[j]}])
+		} else {
+			result = append(result, pairs[[2]int{restA[i], -1}])
+		}
+	}
+	for j := 0; j < m; j++ {
+		if cols[n+j] == j {
+			result = append(result, pairs[[2]int{-1, restB[j]}])
+		}
+	}
 
 	r.diffs = result
 }
 
+// missingCost returns diff count of the value against the empty one,
+// which is comparable with diff count of a pair because keys and brackets are not counted.
+// The value is at the current path, so that IgnorePaths is applied.
+func (r *runner) missingCost(raw rawType) int64 {
+	switch t := raw.(type) {
+	case nil:
+		return 0
+	case rawTypeMap:
+		return int64(r.performDiff(raw, rawTypeMap{}, 0).diffCount)
+	case rawTypeArray:
+		sum := int64(0)
+		for i, v := range t {
+			r.withElementPath(i, func() { sum += r.missingCost(v) })
+		}
+
+		return sum
+	}
+
+	return int64(r.performDiff(missingKey, raw, 0).diffCount)
+}
+
+// similar checks the similarity of the pair is not below the threshold.
+// costA and costB are missingCost of each side.
+func (r *runner) similar(d *diff, costA int64, costB int64) bool {
+	if r.option.similarityThreshold <= 0 || d.status == DiffStatusSame {
+		return true
+	}
+
+	total := costA + costB
+	if total == 0 {
+		return true
+	}
+
+	return 1-float64(d.diffCount)/float64(total) >= r.option.similarityThreshold
+}
+
+// similarPairs returns similar for the pairs of values at indexA and indexB, array elements or documents.
+// missingCost of each value is computed once, with the path of the array element if element is true.
+func (r *runner) similarPairs(valuesA rawTypeArray, valuesB rawTypeArray, element bool) func(d *diff, indexA int, indexB int) bool {
+	if r.option.similarityThreshold <= 0 {
+		return func(*diff, int, int) bool { return true }
+	}
+
+	costs := func(values rawTypeArray) []int64 {
+		c := make([]int64, len(values))
+		for i, v := range values {
+			if !element {
+				c[i] = r.missingCost(v)
+
+				continue
+			}
+
+			r.withElementPath(i, func() { c[i] = r.missingCost(v) })
+		}
+
+		return c
+	}
+
+	costsA, costsB := costs(valuesA), costs(valuesB)
+
+	return func(d *diff, indexA int, indexB int) bool {
+		return r.similar(d, costsA[indexA], costsB[indexB])
+	}
+}
+
 func (r *runner) sortResult() {
 	result := []*YamlDiff{}
 	checked := map[string]interface{}{}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func confirmYaml(t *testing.T, yamlA, yamlB RawYamlList, want string, opts []DoOptionFunc) {
@@ -337,7 +338,6 @@ func TestForSpecificIssues(t *testing.T) {
 		option []DoOptionFunc
 		want   string
 	}{
-		// TODO: It should be extracted as multiline format to understand easily. It needs to refactor (*diff).dump()
 		"#52": {
 			yamlA: `
 data:
@@ -351,8 +351,11 @@ data:
     logging.c: false`,
 			want: `
   data:
--   config: "logging.a: false\nlogging.b: false"
-+   config: "logging.a: false\nlogging.c: false"`,
+    config: |
+      @@ -1,2 +1,2 @@
+      logging.a: false
+-     logging.b: false
++     logging.c: false`,
 		},
 		"#29": {
 			yamlA: `
@@ -388,3 +391,160 @@ special\n\
 		})
 	}
 }
+
+func TestPairDocumentsBy(t *testing.T) {
+	yamlA := mustLoad(t, `
+name: foo
+value: 1
+---
+name: bar
+value: 2
+---
+value: 3`)
+
+	yamlB := mustLoad(t, `
+name: bar
+value: 1
+---
+name: baz
+value: 1
+---
+value: 3`)
+
+	got := [][2]int{}
+	for _, d := range Do(yamlA, yamlB, PairDocumentsBy(DocumentKeyByPaths("name"))) {
+		got = append(got, [2]int{d.indexA, d.indexB})
+	}
+
+	assert.Equal(t, [][2]int{{0, -1}, {1, 0}, {2, 2}, {-1, 1}}, got)
+}
+
+func TestDocumentKeyByPaths(t *testing.T) {
+	fn := DocumentKeyByPaths("kind", "metadata.name", "items[0]")
+
+	got, ok := fn(mustLoad(t, "kind: a\nmetadata:\n  name: b\nitems: [1, 2]")[0].raw)
+	assert.True(t, ok)
+	assert.Equal(t, `"a"/"b"/"1"`, got)
+
+	_, ok = fn(mustLoad(t, "kind: a\nmetadata:\n  name: b")[0].raw)
+	assert.False(t, ok)
+
+	fn = DocumentKeyByPaths("x", "y")
+	keyA, _ := fn(mustLoad(t, "x: a/b\ny: c")[0].raw)
+	keyB, _ := fn(mustLoad(t, "x: a\ny: b/c")[0].raw)
+	assert.NotEqual(t, keyA, keyB)
+}
+
+func TestSelect(t *testing.T) {
+	yamls := mustLoad(t, `
+spec:
+  template:
+    image: app:1
+---
+spec:
+  replicas: 1
+---
+- spec`)
+
+	selected, err := Select(yamls, ".spec.template")
+	require.NoError(t, err)
+
+	got := []interface{}{}
+	for _, y := range selected {
+		got = append(got, y.raw)
+	}
+
+	assert.Equal(t, []interface{}{
+		rawTypeMap{{Key: "image", Value: "app:1"}},
+		nil,
+		nil,
+	}, got)
+
+	all, err := Select(yamls, ".")
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+	assert.Equal(t, yamls[1].raw, all[1].raw)
+
+	for _, path := range []string{"spec.*", "**.image", "spec.unknown"} {
+		_, err := Select(yamls, path)
+		assert.ErrorIs(t, err, ErrInvalidSelectPath, path)
+	}
+}
+
+func TestSelect_originalSource(t *testing.T) {
+	yamlA, err := Select(mustLoad(t, "spec:\n  # the image\n  image: 'app:1' # old\n  replicas: 1\n"), "spec")
+	require.NoError(t, err)
+	yamlB, err := Select(mustLoad(t, "spec:\n  # the image\n  image: 'app:2' # new\n  replicas: 1\n"), "spec")
+	require.NoError(t, err)
+
+	diffs := Do(yamlA, yamlB)
+	require.Len(t, diffs, 1)
+
+	assert.Equal(t, "- # the image\n- image: 'app:1' # old\n+ # the image\n+ image: 'app:2' # new\n  replicas: 1\n", diffs[0].DumpWith(OriginalSource()))
+}
+
+func TestDo_optimalPairing(t *testing.T) {
+	// greedy pairing takes {0, 0} first because it's the smallest, but the total is larger
+	yamlA := mustLoad(t, "v: ab\n---\nv: abcd")
+	yamlB := mustLoad(t, "v: abx\n---\nv: b")
+
+	got := [][2]int{}
+	for _, d := range Do(yamlA, yamlB) {
+		got = append(got, [2]int{d.indexA, d.indexB})
+	}
+
+	assert.Equal(t, [][2]int{{0, 1}, {1, 0}}, got)
+}
+
+func TestDo_singlePair(t *testing.T) {
+	diffs := Do(mustLoad(t, "a: 1"), mustLoad(t, "b: 2"))
+	require.Len(t, diffs, 1)
+
+	assert.Equal(t, 0, diffs[0].indexA)
+	assert.Equal(t, 0, diffs[0].indexB)
+	assert.Equal(t, "- a: 1\n+ b: 2\n", diffs[0].Dump())
+}
+
+func TestSimilarityThreshold(t *testing.T) {
+	yamlA := mustLoad(t, "name: foo\nimage: app:1\n---\nname: bar\nimage: aaaaaaaa")
+	yamlB := mustLoad(t, "name: foo\nimage: app:2\n---\nname: bar\nimage: bbbbbbbbbbb")
+
+	tests := map[string]struct {
+		opts []DoOptionFunc
+		want [][2]int
+	}{
+		"default": {
+			want: [][2]int{{0, 0}, {1, 1}},
+		},
+		"threshold": {
+			opts: []DoOptionFunc{SimilarityThreshold(0.6)},
+			want: [][2]int{{0, 0}, {1, -1}, {-1, 1}},
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			got := [][2]int{}
+			for _, d := range Do(yamlA, yamlB, tc.opts...) {
+				got = append(got, [2]int{d.indexA, d.indexB})
+			}
+
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestSimilarityThreshold_ignorePaths(t *testing.T) {
+	description := strings.Repeat("long description ", 10)
+	yamlA := mustLoad(t, "description: "+description+"\ncontainers:\n- name: abcd\n  image: xxxxxxxxxxxxxxxxxxxxxxxx\n")
+	yamlB := mustLoad(t, "description: "+description+"\ncontainers:\n- name: wxyz\n  image: yyyyyyyyyyyyyyyyyyyyyyyy\n")
+
+	// the ignored image doesn't make the elements similar
+	diffs := Do(yamlA, yamlB, SimilarityThreshold(0.6), IgnorePaths("containers[*].image"))
+	require.Len(t, diffs, 1)
+
+	kinds := []ChangeKind{}
+	for _, c := range diffs[0].Changes() {
+		kinds = append(kinds, c.Kind)
+	}
+	assert.Equal(t, []ChangeKind{ChangeKindRemoved, ChangeKindAdded}, kinds)
+}